@@ -7,10 +7,8 @@ require (
 	github.com/mattn/go-isatty v0.0.19
 	github.com/michaelmacinnis/adapted v0.7.1
 	github.com/peterh/liner v1.2.2
+	github.com/rivo/uniseg v0.4.4
 	golang.org/x/sys v0.12.0
 )
 
-require (
-	github.com/mattn/go-runewidth v0.0.15 // indirect
-	github.com/rivo/uniseg v0.4.4 // indirect
-)
+require github.com/mattn/go-runewidth v0.0.15 // indirect
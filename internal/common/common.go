@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // Error wraps a string as an error.
@@ -21,7 +22,7 @@ func (e Error) Error() string {
 func String(c cell.I) string {
 	b, ok := c.(fmt.Stringer)
 	if !ok {
-		panic(c.Name() + " cannot be used in a string context")
+		panic(exception.New(exception.TypeError, c.Name()+" cannot be used in a string context"))
 	}
 
 	return b.String()
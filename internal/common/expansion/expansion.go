@@ -0,0 +1,273 @@
+// Released under an MIT license. See LICENSE.
+
+// Package expansion parses the text of a parameter expansion, ${...}, and
+// provides the string operations it can apply to a value.
+package expansion
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// T (expansion) is a parsed parameter expansion. Words, patterns and
+// indices are left as written. They may contain references of their own
+// and are interpolated when the expansion is evaluated.
+type T struct {
+	Name    string // The variable name.
+	Index   string // The text between '[' and ']', if Indexed.
+	Indexed bool   // True if the expansion selects a list element.
+	Length  bool   // True for ${#name}.
+	Op      string // One of "", ":-", ":?", "#", "##", "%", "%%", "/" or "//".
+	Word    string // The default, message or pattern.
+	With    string // The replacement for "/" and "//".
+}
+
+type expansion = T
+
+// Error is a syntax error at Offset in the text of an expansion.
+type Error struct {
+	Offset  int
+	Message string
+}
+
+// Error returns the message for the syntax error e.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Operators, longest first so that "##" is found before "#".
+//
+//nolint:gochecknoglobals
+var operators = []string{":-", ":?", "##", "#", "%%", "%", "//", "/"}
+
+// Parse parses s, the text of an expansion without its enclosing "${" and
+// "}". The syntax is,
+//
+//	'#' <reference> | <reference> [<operator> <word>]
+//
+// where a reference is a name optionally followed by an index in brackets.
+func Parse(s string) (*T, error) {
+	e := &expansion{}
+
+	i := 0
+	if len(s) > 1 && s[0] == '#' {
+		e.Length = true
+		i++
+	}
+
+	j := i + strings.IndexAny(s[i:], ":#%/[]")
+	if j < i {
+		j = len(s)
+	}
+
+	if j == i {
+		return nil, &Error{i, "expected a name"}
+	}
+
+	e.Name = s[i:j]
+
+	if j < len(s) && s[j] == '[' {
+		k := closing(s, j)
+		if k < 0 {
+			return nil, &Error{len(s), "expected ']'"}
+		}
+
+		e.Index = s[j+1 : k]
+		e.Indexed = true
+
+		if e.Index == "" {
+			return nil, &Error{k, "expected an index"}
+		}
+
+		j = k + 1
+	}
+
+	if j == len(s) {
+		return e, nil
+	}
+
+	if e.Length {
+		return nil, &Error{j, "unexpected '" + s[j:] + "' after length"}
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(s[j:], op) {
+			e.Op = op
+
+			break
+		}
+	}
+
+	if e.Op == "" {
+		return nil, &Error{j, "unknown operator '" + s[j:] + "'"}
+	}
+
+	j += len(e.Op)
+	e.Word = s[j:]
+
+	if e.Op[0] == '/' {
+		if k := separator(e.Word); k >= 0 {
+			e.With = e.Word[k+1:]
+			e.Word = e.Word[:k]
+		}
+
+		if e.Word == "" {
+			return nil, &Error{j, "expected a pattern"}
+		}
+	}
+
+	return e, nil
+}
+
+// Transform applies the pattern operator of the expansion e to s. The
+// pattern and replacement are passed separately as, by now, they have
+// been interpolated.
+func (e *expansion) Transform(s, pattern, with string) string {
+	switch e.Op {
+	case "#", "##":
+		return strip(s, pattern, false, e.Op == "##")
+	case "%", "%%":
+		return strip(s, pattern, true, e.Op == "%%")
+	case "/", "//":
+		r := regexp.MustCompile(Regexp(pattern))
+		r.Longest()
+
+		if e.Op == "//" {
+			return r.ReplaceAllLiteralString(s, with)
+		}
+
+		loc := r.FindStringIndex(s)
+		if loc == nil {
+			return s
+		}
+
+		return s[:loc[0]] + with + s[loc[1]:]
+	}
+
+	return s
+}
+
+// Regexp returns a regular expression equivalent to the glob pattern p.
+// Unlike file name globs, '*' and '?' also match '/'.
+func Regexp(p string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			b.WriteString("(?s:.*)")
+		case '?':
+			b.WriteString("(?s:.)")
+		case '[':
+			k := strings.IndexByte(p[i+1:], ']')
+			if k < 0 {
+				b.WriteString(`\[`)
+
+				continue
+			}
+
+			class := p[i+1 : i+1+k]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+
+			i += k + 1
+		case '\\':
+			if i+1 < len(p) {
+				i++
+			}
+
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		default:
+			_, w := utf8.DecodeRuneInString(p[i:])
+			b.WriteString(regexp.QuoteMeta(p[i : i+w]))
+
+			i += w - 1
+		}
+	}
+
+	return b.String()
+}
+
+// Closing returns the index of the ']' that matches the '[' at s[i] or -1.
+func closing(s string, i int) int {
+	depth := 0
+
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Separator returns the index of the first '/' in s that is neither
+// escaped nor inside a nested reference or -1.
+func separator(s string) int {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '$':
+			if i+1 < len(s) && s[i+1] == '{' {
+				depth++
+				i++
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case '/':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Strip removes the shortest, or longest, prefix, or suffix, of s that
+// matches the glob pattern p. If nothing matches, s is returned unchanged.
+func strip(s, p string, suffix, longest bool) string {
+	r := regexp.MustCompile("^(?:" + Regexp(p) + ")$")
+
+	// Character boundaries, in the order they are tried.
+	bounds := []int{}
+
+	for i := range s {
+		bounds = append(bounds, i)
+	}
+
+	bounds = append(bounds, len(s))
+
+	if suffix != longest {
+		for i, j := 0, len(bounds)-1; i < j; i, j = i+1, j-1 {
+			bounds[i], bounds[j] = bounds[j], bounds[i]
+		}
+	}
+
+	for _, b := range bounds {
+		if !suffix && r.MatchString(s[:b]) {
+			return s[b:]
+		}
+
+		if suffix && r.MatchString(s[b:]) {
+			return s[:b]
+		}
+	}
+
+	return s
+}
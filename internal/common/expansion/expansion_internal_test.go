@@ -0,0 +1,73 @@
+// Released under an MIT license. See LICENSE.
+
+package expansion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]T{
+		"x":           {Name: "x"},
+		"#x":          {Name: "x", Length: true},
+		"#l[$i]":      {Name: "l", Index: "$i", Indexed: true, Length: true},
+		"my-var:-a":   {Name: "my-var", Op: ":-", Word: "a"},
+		"x:?":         {Name: "x", Op: ":?"},
+		"x##*/":       {Name: "x", Op: "##", Word: "*/"},
+		"x%.*":        {Name: "x", Op: "%", Word: ".*"},
+		"x//a/b":      {Name: "x", Op: "//", Word: "a", With: "b"},
+		"x/${y/z}/":   {Name: "x", Op: "/", Word: "${y/z}"},
+		`x/a\/b/c`:    {Name: "x", Op: "/", Word: `a\/b`, With: "c"},
+		"l[-1]:-${d}": {Name: "l", Index: "-1", Indexed: true, Op: ":-", Word: "${d}"},
+	} {
+		e, err := Parse(s)
+		if err != nil || *e != expected {
+			t.Fatalf("%q: expected %+v, got %+v (%v)", s, expected, e, err)
+		}
+	}
+
+	for s, offset := range map[string]int{
+		"":      0,
+		"#":     0,
+		":-x":   0,
+		"l[]":   2,
+		"l[1":   3,
+		"#x:-y": 2,
+		"x:=y":  1,
+		"x/":    2,
+	} {
+		_, err := Parse(s)
+
+		var e *Error
+		if !errors.As(err, &e) || e.Offset != offset {
+			t.Fatalf("%q: expected an error at %d, got %v", s, offset, err)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	for _, c := range []struct {
+		op, s, pattern, with, expected string
+	}{
+		{"#", "a/b/c", "*/", "", "b/c"},
+		{"##", "a/b/c", "*/", "", "c"},
+		{"%", "a.tar.gz", ".*", "", "a.tar"},
+		{"%%", "a.tar.gz", ".*", "", "a"},
+		{"#", "héllo", "h?", "", "llo"},
+		{"%", "file", "x*", "", "file"},
+		{"/", "aXbXc", "X", "-", "a-bXc"},
+		{"//", "aXbXc", "X", "-", "a-b-c"},
+		{"/", "a.b.c", "*.", "", "c"},
+		{"//", "a1b22c", "[0-9]", "#", "a#b##c"},
+		{"//", "a1b22c", "[!0-9]", "", "122"},
+		{"/", "a*b", `\*`, "+", "a+b"},
+	} {
+		e := &expansion{Op: c.op}
+
+		actual := e.Transform(c.s, c.pattern, c.with)
+		if actual != c.expected {
+			t.Fatalf("%s %q %q %q: expected %q, got %q", c.op, c.s, c.pattern, c.with, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Released under an MIT license. See LICENSE.
+
+// Package format implements fmt.Formatter for oh's cell types.
+//
+// In addition to the usual verbs, cells can be formatted with %l for their
+// literal representation, %Q for their text quoted so that it is safe to
+// pass to a POSIX shell, and %j for their JSON representation.
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+)
+
+// Bits of precision used when formatting exact numbers as decimals, in
+// addition to the bits needed for the number itself.
+const precision = 64
+
+type boolean interface {
+	Bool() bool
+}
+
+type inexact interface {
+	Inexact() bool
+}
+
+// Cell writes the cell c to f as specified by verb.
+func Cell(f fmt.State, verb rune, c cell.I) {
+	switch verb {
+	case 'j':
+		b, err := JSON(c)
+		if err != nil {
+			bad(f, verb, c)
+
+			return
+		}
+
+		pad(f, string(b))
+
+	case 'l':
+		l, ok := c.(literal.I)
+		if !ok {
+			bad(f, verb, c)
+
+			return
+		}
+
+		pad(f, l.Literal())
+
+	case 'Q':
+		s, ok := c.(fmt.Stringer)
+		if !ok {
+			bad(f, verb, c)
+
+			return
+		}
+
+		pad(f, Quote(s.String()))
+
+	case 'q', 's', 'v':
+		s, ok := c.(fmt.Stringer)
+		if !ok {
+			bad(f, verb, c)
+
+			return
+		}
+
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s.String())
+
+	case 't':
+		b, ok := c.(boolean)
+
+		fmt.Fprintf(f, fmt.FormatString(f, verb), !ok || b.Bool())
+
+	case 'b', 'c', 'd', 'o', 'O', 'U', 'x', 'X':
+		if integer(f, verb, c) {
+			return
+		}
+
+		if s, ok := c.(fmt.Stringer); ok && (verb == 'x' || verb == 'X') {
+			fmt.Fprintf(f, fmt.FormatString(f, verb), s.String())
+
+			return
+		}
+
+		bad(f, verb, c)
+
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		if !float(f, verb, c) {
+			bad(f, verb, c)
+		}
+
+	default:
+		bad(f, verb, c)
+	}
+}
+
+// JSON returns the JSON representation of c. Only cells that implement
+// json.Marshaler have a JSON representation.
+func JSON(c cell.I) ([]byte, error) {
+	if c == nil {
+		return []byte("null"), nil
+	}
+
+	m, ok := c.(json.Marshaler)
+	if !ok {
+		return nil, common.Error(c.Name() + " does not have a JSON representation")
+	}
+
+	return m.MarshalJSON()
+}
+
+// JSONString returns s as a JSON string.
+func JSONString(s string) []byte {
+	var b bytes.Buffer
+
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+
+	// Encoding a string never fails.
+	_ = e.Encode(s)
+
+	return bytes.TrimRight(b.Bytes(), "\n")
+}
+
+// Quote returns s quoted, if necessary, so that it is safe to pass to a
+// POSIX shell.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	if strings.IndexFunc(s, unsafe) < 0 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func bad(f fmt.State, verb rune, c cell.I) {
+	fmt.Fprintf(f, "%%!%c(%s", verb, c.Name())
+
+	if s, ok := c.(fmt.Stringer); ok {
+		fmt.Fprintf(f, "=%s", s.String())
+	}
+
+	fmt.Fprint(f, ")")
+}
+
+func float(f fmt.State, verb rune, c cell.I) bool {
+	r, ok := number(c)
+	if !ok {
+		return false
+	}
+
+	// Approximate numbers, and numbers formatted with %g and no precision,
+	// are formatted like any other float64.
+	p, hasPrecision := f.Precision()
+	if n, ok := c.(inexact); ok && n.Inexact() || !hasPrecision && (verb == 'g' || verb == 'G') {
+		v, _ := r.Float64()
+
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v)
+
+		return true
+	}
+
+	// More than enough bits for the requested number of decimal digits.
+	bits := uint(r.Num().BitLen() + r.Denom().BitLen() + precision + 4*p)
+
+	new(big.Float).SetPrec(bits).SetRat(r).Format(f, verb)
+
+	return true
+}
+
+func integer(f fmt.State, verb rune, c cell.I) bool {
+	r, ok := number(c)
+	if !ok || !r.IsInt() {
+		return false
+	}
+
+	i := r.Num()
+
+	if verb == 'c' || verb == 'U' {
+		if !i.IsInt64() {
+			return false
+		}
+
+		fmt.Fprintf(f, fmt.FormatString(f, verb), rune(i.Int64()))
+
+		return true
+	}
+
+	i.Format(f, verb)
+
+	return true
+}
+
+func number(c cell.I) (r *big.Rat, ok bool) {
+	n, ok := c.(rational.I)
+	if !ok {
+		return nil, false
+	}
+
+	// Not every symbol is a number.
+	defer func() {
+		if recover() != nil {
+			r, ok = nil, false
+		}
+	}()
+
+	return n.Rat(), true
+}
+
+func pad(f fmt.State, s string) {
+	fmt.Fprintf(f, fmt.FormatString(f, 's'), s)
+}
+
+func unsafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+
+	return !strings.ContainsRune("%+,-./:=@_", r)
+}
@@ -5,6 +5,7 @@ package conduit
 
 import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // I (conduit) is the interface oh channels and pipes satisfy.
@@ -35,5 +36,5 @@ func To(c cell.I) I {
 		return t
 	}
 
-	panic("not a conduit")
+	panic(exception.New(exception.TypeError, "not a conduit"))
 }
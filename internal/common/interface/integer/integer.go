@@ -8,6 +8,7 @@ import (
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
 )
 
@@ -23,7 +24,7 @@ func Value(c cell.I) int64 {
 			}
 		}
 
-		panic(c.Name() + " does not have an integer value")
+		panic(exception.New(exception.TypeError, c.Name()+" does not have an integer value"))
 	}
 
 	s, isSym := c.(*sym.T)
@@ -34,5 +35,5 @@ func Value(c cell.I) int64 {
 		}
 	}
 
-	panic(c.Name() + " cannot be converted to an integer value")
+	panic(exception.New(exception.TypeError, c.Name()+" cannot be converted to an integer value"))
 }
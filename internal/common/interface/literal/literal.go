@@ -5,6 +5,7 @@ package literal
 
 import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // I (literal) is any type that can be expressed as a literal.
@@ -17,7 +18,7 @@ func String(c cell.I) string {
 	l, ok := c.(I)
 	if !ok {
 		// Not all cell types can be expressed as literals.
-		panic(c.Name() + " does not have a literal representation")
+		panic(exception.New(exception.TypeError, c.Name()+" does not have a literal representation"))
 	}
 
 	return l.Literal()
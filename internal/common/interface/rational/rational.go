@@ -7,6 +7,7 @@ import (
 	"math/big"
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // I (rational) is anything that can be treated as a rational number in oh.
@@ -21,7 +22,7 @@ func Number(c cell.I) *big.Rat {
 	r, ok := c.(rational)
 	if !ok {
 		// Not all cell types can be treated as numbers.
-		panic(c.Name() + " cannot be use in a numeric context")
+		panic(exception.New(exception.TypeError, c.Name()+" cannot be used in a numeric context"))
 	}
 
 	return r.Rat()
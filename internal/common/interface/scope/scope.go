@@ -7,6 +7,7 @@ import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/reference"
 	"github.com/michaelmacinnis/oh/internal/common/struct/hash"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // I (scope) is the interface for oh's first-class environments and objects.
@@ -20,6 +21,7 @@ type I interface {
 	Define(k string, v cell.I)
 	Export(k string, v cell.I)
 	Lookup(k string) reference.I
+	Members() []string
 	Public() *hash.T
 	Remove(k string) bool
 
@@ -42,5 +44,5 @@ func To(c cell.I) scope {
 		return t
 	}
 
-	panic(c.Name() + " cannot be used in an object context")
+	panic(exception.New(exception.TypeError, c.Name()+" cannot be used in an object context"))
 }
@@ -5,6 +5,7 @@ package hash
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
@@ -89,6 +90,25 @@ func (h *hash) Get(k string) reference.I {
 	return h.m[k]
 }
 
+// Keys returns the names in the hash h in sorted order.
+func (h *hash) Keys() []string {
+	if h == nil {
+		return nil
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+
+	keys := make([]string, 0, len(h.m))
+	for k := range h.m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 // Set associates the name k with the cell v in the hash h.
 func (h *hash) Set(k string, v cell.I) {
 	h.Lock()
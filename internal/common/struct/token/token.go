@@ -27,12 +27,15 @@ const (
 	Error Class = iota
 
 	Andf Class = unicode.MaxRune + iota
+	Arithmetic
 	Background
+	Descriptor
 	DollarSingleQuoted
 	DoubleQuoted
 	MetaClose
 	MetaOpen
 	Orf
+	Parameter
 	Pipe
 	Redirect
 	SingleQuoted
@@ -57,8 +60,12 @@ func (c *Class) String() string {
 		return "Error"
 	case Andf:
 		return "Andf"
+	case Arithmetic:
+		return "Arithmetic"
 	case Background:
 		return "Background"
+	case Descriptor:
+		return "Descriptor"
 	case DollarSingleQuoted:
 		return "DollarSingleQuoted"
 	case DoubleQuoted:
@@ -69,6 +76,8 @@ func (c *Class) String() string {
 		return "MetaOpen"
 	case Orf:
 		return "Orf"
+	case Parameter:
+		return "Parameter"
 	case Pipe:
 		return "Pipe"
 	case Redirect:
@@ -0,0 +1,109 @@
+// Released under an MIT license. See LICENSE.
+
+// Package blob provides oh's bytes type for binary data.
+package blob
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
+
+const name = "bytes"
+
+// T (blob) is a sequence of bytes. Unlike a string, it is not text.
+type T struct {
+	b []byte
+}
+
+type blob = T
+
+// Hex creates a blob from hexadecimal text.
+func Hex(s string) cell.I {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' is not valid hexadecimal"))
+	}
+
+	return New(b)
+}
+
+// New creates a blob containing the bytes b.
+func New(b []byte) cell.I {
+	return &blob{b}
+}
+
+// Bool returns false for an empty blob and true otherwise.
+func (b *blob) Bool() bool {
+	return len(b.b) > 0
+}
+
+// Bytes returns the bytes in the blob b.
+func (b *blob) Bytes() []byte {
+	return b.b
+}
+
+// Equal returns true if c is a blob containing the same bytes as b.
+func (b *blob) Equal(c cell.I) bool {
+	return Is(c) && bytes.Equal(b.b, To(c).b)
+}
+
+// Format implements fmt.Formatter for the blob b.
+func (b *blob) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, b)
+}
+
+// Literal returns the literal representation of the blob b.
+func (b *blob) Literal() string {
+	if len(b.b) == 0 {
+		return "(|" + name + ` ''|)`
+	}
+
+	return "(|" + name + " " + b.String() + "|)"
+}
+
+// MarshalJSON returns the JSON representation, in base64, of the blob b.
+func (b *blob) MarshalJSON() ([]byte, error) {
+	return format.JSONString(base64.StdEncoding.EncodeToString(b.b)), nil
+}
+
+// Name returns the name of the blob type.
+func (b *blob) Name() string {
+	return name
+}
+
+// String returns the bytes in the blob b as hexadecimal text.
+func (b *blob) String() string {
+	return hex.EncodeToString(b.b)
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t blob
+
+	// The blob type has a boolean value.
+	_ = boolean.I(&t)
+
+	// The blob type is a cell.
+	_ = cell.I(&t)
+
+	// The blob type has a literal representation.
+	_ = literal.I(&t)
+
+	// The blob type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The blob type is a stringer.
+	_ = fmt.Stringer(&t)
+
+	// The blob type has a JSON representation.
+	_ = json.Marshaler(&t)
+}
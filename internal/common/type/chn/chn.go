@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/michaelmacinnis/adapted"
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/conduit"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
@@ -43,6 +44,11 @@ func (*chn) Name() string {
 	return name
 }
 
+// Format implements fmt.Formatter for the chn c.
+func (c *chn) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, c)
+}
+
 // Read reads a cell from the chn.
 func (c *chn) Read() cell.I {
 	v := <-*c
@@ -98,4 +104,7 @@ func implements() { //nolint:deadcode,unused
 
 	// The chn type is a conduit.
 	_ = conduit.I(&t)
+
+	// The chn type is a formatter.
+	_ = fmt.Formatter(&t)
 }
@@ -3,7 +3,10 @@
 // Released under an MIT license. See LICENSE.
 package chn
 
-import "github.com/michaelmacinnis/oh/internal/common/interface/cell"
+import (
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
 
 // Is returns true if c is a *T.
 func Is(c cell.I) bool {
@@ -18,5 +21,5 @@ func To(c cell.I) *T {
 		return t
 	}
 
-	panic("not a " + name)
+	panic(exception.New(exception.TypeError, "not a "+name))
 }
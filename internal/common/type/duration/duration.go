@@ -0,0 +1,103 @@
+// Released under an MIT license. See LICENSE.
+
+// Package duration provides oh's duration type.
+package duration
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
+
+const name = "duration"
+
+// T (duration) is the time elapsed between two instants.
+type T struct {
+	d time.Duration
+}
+
+type duration = T
+
+// Of creates a duration for the time.Duration d.
+func Of(d time.Duration) cell.I {
+	return &duration{d}
+}
+
+// New creates a duration from text like "1h30m" or "250ms".
+func New(s string) cell.I {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' is not a valid duration"))
+	}
+
+	return Of(d)
+}
+
+// Bool returns false for a zero length duration and true otherwise.
+func (d *duration) Bool() bool {
+	return d.d != 0
+}
+
+// Duration returns the value of the duration d as a time.Duration.
+func (d *duration) Duration() time.Duration {
+	return d.d
+}
+
+// Equal returns true if c is a duration of the same length as d.
+func (d *duration) Equal(c cell.I) bool {
+	return Is(c) && d.d == To(c).d
+}
+
+// Format implements fmt.Formatter for the duration d.
+func (d *duration) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, d)
+}
+
+// Literal returns the literal representation of the duration d.
+func (d *duration) Literal() string {
+	return "(|" + name + " " + d.String() + "|)"
+}
+
+// MarshalJSON returns the JSON representation of the duration d.
+func (d *duration) MarshalJSON() ([]byte, error) {
+	return format.JSONString(d.String()), nil
+}
+
+// Name returns the name of the duration type.
+func (d *duration) Name() string {
+	return name
+}
+
+// String returns the text of the duration d.
+func (d *duration) String() string {
+	return d.d.String()
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t duration
+
+	// The duration type has a boolean value.
+	_ = boolean.I(&t)
+
+	// The duration type is a cell.
+	_ = cell.I(&t)
+
+	// The duration type has a literal representation.
+	_ = literal.I(&t)
+
+	// The duration type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The duration type is a stringer.
+	_ = fmt.Stringer(&t)
+
+	// The duration type has a JSON representation.
+	_ = json.Marshaler(&t)
+}
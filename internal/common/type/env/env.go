@@ -4,6 +4,9 @@
 package env
 
 import (
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/reference"
 	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
@@ -69,6 +72,11 @@ func (e *env) Expose() scope.I {
 	return e
 }
 
+// Format implements fmt.Formatter for the env e.
+func (e *env) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, e)
+}
+
 // Lookup retrieves the reference associated with the name k in the env e.
 func (e *env) Lookup(k string) reference.I {
 	if e == nil {
@@ -88,6 +96,32 @@ func (e *env) Lookup(k string) reference.I {
 	return v
 }
 
+// Members returns the sorted names, public and private, defined in the env e.
+// Names in enclosing scopes are not included.
+func (e *env) Members() []string {
+	private := e.private.Keys()
+	public := e.public.Keys()
+
+	members := make([]string, 0, len(private)+len(public))
+
+	for len(private) > 0 || len(public) > 0 {
+		switch {
+		case len(public) == 0 || len(private) > 0 && private[0] < public[0]:
+			members = append(members, private[0])
+			private = private[1:]
+		case len(private) == 0 || public[0] < private[0]:
+			members = append(members, public[0])
+			public = public[1:]
+		default:
+			members = append(members, private[0])
+			private = private[1:]
+			public = public[1:]
+		}
+	}
+
+	return members
+}
+
 // Name returns the type name for the env e.
 func (e *env) Name() string {
 	return name
@@ -149,4 +183,7 @@ func implements() { //nolint:deadcode,unused
 
 	// The env type is a scope.
 	_ = scope.I(&t)
+
+	// The env type is a formatter.
+	_ = fmt.Formatter(&t)
 }
@@ -0,0 +1,32 @@
+// Released under an MIT license. See LICENSE.
+
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+)
+
+func TestMembers(t *testing.T) {
+	outer := New(nil)
+	outer.Define("hidden", num.Int(0))
+
+	e := New(outer)
+	e.Define("c", num.Int(1))
+	e.Define("a", num.Int(2))
+	e.Export("b", num.Int(3))
+	e.Export("a", num.Int(4))
+	e.Export("d", num.Int(5))
+
+	members := strings.Join(e.Members(), " ")
+	if members != "a b c d" {
+		t.Fatalf("expected 'a b c d', got '%s'", members)
+	}
+
+	public := strings.Join(e.Public().Keys(), " ")
+	if public != "a b d" {
+		t.Fatalf("expected 'a b d', got '%s'", public)
+	}
+}
@@ -0,0 +1,158 @@
+// Released under an MIT license. See LICENSE.
+
+// Package exception provides oh's exception type.
+//
+// Exceptions are raised, as panics, by Go code and are thrown, like any
+// other value, by oh code. As this package is used by almost every other
+// package it depends only on the cell interface and the loc struct.
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/struct/loc"
+)
+
+const name = "exception"
+
+// Kinds of exceptions raised by oh.
+const (
+	Arithmetic = "arithmetic"  // A calculation has no result or overflows.
+	Error      = "error"       // Anything without a more specific kind.
+	ExecFailed = "exec-failed" // An external command could not be run.
+	IO         = "io"          // A file or pipe operation failed.
+	Import     = "import"      // A module could not be found or imported.
+	NotDefined = "not-defined" // A name could not be resolved.
+	Restricted = "restricted"  // A restricted mode policy forbids an action.
+	Syntax     = "syntax"      // Text could not be parsed.
+	TypeError  = "type-error"  // A value or argument list has the wrong type.
+)
+
+// T (exception) is an error with a kind, a message and the location and
+// trace of the code that raised it.
+type T struct {
+	cause   cell.I
+	errno   int
+	kind    string
+	loc     *loc.T
+	message string
+	trace   cell.I
+}
+
+type exception = T
+
+// New creates an exception of the given kind.
+func New(kind, message string) *T {
+	return &exception{kind: kind, message: message}
+}
+
+// Wrap creates an exception of the given kind for the Go error err. If err
+// was caused by a system call the exception's errno is set.
+func Wrap(kind string, err error) *T {
+	e := New(kind, err.Error())
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		e.errno = int(errno)
+	}
+
+	return e
+}
+
+// Because sets the cause of the exception e and returns e.
+func (e *exception) Because(c cell.I) *T {
+	e.cause = c
+
+	return e
+}
+
+// Cause returns the value that caused the exception e, or nil.
+func (e *exception) Cause() cell.I {
+	return e.cause
+}
+
+// Equal returns true if c is the exception e.
+func (e *exception) Equal(c cell.I) bool {
+	return Is(c) && e == To(c)
+}
+
+// Errno returns the system error number for the exception e, or 0.
+func (e *exception) Errno() int {
+	return e.errno
+}
+
+// Error returns the message for the exception e.
+func (e *exception) Error() string {
+	return e.message
+}
+
+// Format implements fmt.Formatter for the exception e.
+func (e *exception) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'q', 's', 'v':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), e.message)
+	default:
+		fmt.Fprintf(f, "%%!%c(%s=%s)", verb, name, e.message)
+	}
+}
+
+// Kind returns the kind of the exception e.
+func (e *exception) Kind() string {
+	return e.kind
+}
+
+// Loc returns the location where the exception e was raised, or nil.
+func (e *exception) Loc() *loc.T {
+	return e.loc
+}
+
+// Message returns the message for the exception e.
+func (e *exception) Message() string {
+	return e.message
+}
+
+// Name returns the name of the exception type.
+func (e *exception) Name() string {
+	return name
+}
+
+// Raised records the location and trace of the code that raised the
+// exception e, if these have not already been recorded, and returns e.
+func (e *exception) Raised(l *loc.T, trace cell.I) *T {
+	if e.loc == nil {
+		e.loc = l
+		e.trace = trace
+	}
+
+	return e
+}
+
+// String returns the message for the exception e.
+func (e *exception) String() string {
+	return e.message
+}
+
+// Trace returns the trace captured when the exception e was raised, or nil.
+func (e *exception) Trace() cell.I {
+	return e.trace
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t exception
+
+	// The exception type is a cell.
+	_ = cell.I(&t)
+
+	// The exception type is an error.
+	_ = error(&t)
+
+	// The exception type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The exception type is a stringer.
+	_ = fmt.Stringer(&t)
+}
@@ -0,0 +1,45 @@
+// Released under an MIT license. See LICENSE.
+
+package exception
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/struct/loc"
+)
+
+func TestRaised(t *testing.T) {
+	e := New(NotDefined, "'x' not defined")
+
+	first := &loc.T{Name: "first"}
+	e.Raised(first, nil)
+	e.Raised(&loc.T{Name: "second"}, nil)
+
+	if e.Loc() != first {
+		t.Fatalf("expected location to be %v, got %v", first, e.Loc())
+	}
+}
+
+func TestWrap(t *testing.T) {
+	_, err := os.Open("/no/such/file")
+
+	e := Wrap(IO, err)
+	if e.Kind() != IO {
+		t.Fatalf("expected kind %q, got %q", IO, e.Kind())
+	}
+
+	if e.Errno() != int(syscall.ENOENT) {
+		t.Fatalf("expected errno %d, got %d", syscall.ENOENT, e.Errno())
+	}
+
+	if got := fmt.Sprintf("%v", e); got != err.Error() {
+		t.Fatalf("expected %q, got %q", err.Error(), got)
+	}
+
+	if e := Wrap(Error, fmt.Errorf("no errno")); e.Errno() != 0 {
+		t.Fatalf("expected no errno, got %d", e.Errno())
+	}
+}
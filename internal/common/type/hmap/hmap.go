@@ -0,0 +1,335 @@
+// Released under an MIT license. See LICENSE.
+
+// Package hmap provides oh's map type.
+package hmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/status"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+)
+
+const name = "map"
+
+type entry struct {
+	k cell.I
+	v cell.I
+}
+
+// T (hmap) is oh's map type. Keys may be any cell and are compared using
+// Equal. Entries are kept in the order in which they were first added.
+type T struct {
+	sync.RWMutex
+	entries []*entry
+	index   map[string][]*entry
+}
+
+type hmap = T
+
+// New creates a new map from a sequence of alternating keys and values.
+func New(kvs ...cell.I) cell.I {
+	if len(kvs)%2 != 0 {
+		panic(exception.New(exception.TypeError, "expected a value for each key"))
+	}
+
+	m := &hmap{index: map[string][]*entry{}}
+
+	for i := 0; i < len(kvs); i += 2 {
+		m.Set(kvs[i], kvs[i+1])
+	}
+
+	return m
+}
+
+// Del removes the entry for the key k, if any, from the map m.
+// It returns true if there was an entry.
+func (m *hmap) Del(k cell.I) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	h := hash(k)
+
+	bucket := m.index[h]
+	for i, e := range bucket {
+		if !e.k.Equal(k) {
+			continue
+		}
+
+		bucket = append(bucket[:i], bucket[i+1:]...)
+		if len(bucket) == 0 {
+			delete(m.index, h)
+		} else {
+			m.index[h] = bucket
+		}
+
+		for j, o := range m.entries {
+			if o == e {
+				m.entries = append(m.entries[:j], m.entries[j+1:]...)
+
+				break
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Equal returns true if c is a map with the same keys and values as m.
+func (m *hmap) Equal(c cell.I) bool {
+	if !Is(c) {
+		return false
+	}
+
+	o := To(c)
+	if o == m {
+		return true
+	}
+
+	if o.Length() != m.Length() {
+		return false
+	}
+
+	for _, e := range m.snapshot() {
+		v, ok := o.Get(e.k)
+		if !ok || !v.Equal(e.v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Format implements fmt.Formatter for the map m.
+func (m *hmap) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, m)
+}
+
+// Get returns the value for the key k and true, if there is an entry for k.
+func (m *hmap) Get(k cell.I) (cell.I, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	e := m.lookup(k)
+	if e == nil {
+		return nil, false
+	}
+
+	return e.v, true
+}
+
+// Items returns a list of (key . value) pairs in insertion order.
+func (m *hmap) Items() cell.I {
+	return m.list(func(e *entry) cell.I {
+		return pair.Cons(e.k, e.v)
+	})
+}
+
+// Keys returns a list of the map's keys in insertion order.
+func (m *hmap) Keys() cell.I {
+	return m.list(func(e *entry) cell.I {
+		return e.k
+	})
+}
+
+// Length returns the number of entries in the map m.
+func (m *hmap) Length() int {
+	m.RLock()
+	defer m.RUnlock()
+
+	return len(m.entries)
+}
+
+// Literal returns the literal representation of the map m.
+func (m *hmap) Literal() string {
+	return m.string(literal.String)
+}
+
+// MarshalJSON returns the JSON representation of the map m.
+func (m *hmap) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+
+	for i, e := range m.snapshot() {
+		k, ok := e.k.(fmt.Stringer)
+		if !ok {
+			return nil, common.Error(e.k.Name() + " cannot be used as a JSON key")
+		}
+
+		v, err := format.JSON(e.v)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = append(b, format.JSONString(k.String())...)
+		b = append(b, ':')
+		b = append(b, v...)
+	}
+
+	return append(b, '}'), nil
+}
+
+// Merge returns a new map with the entries of m followed by the entries of
+// each map in others. Later entries replace the values of earlier entries
+// with the same key.
+func (m *hmap) Merge(others ...*T) cell.I {
+	r := New().(*hmap)
+
+	for _, o := range append([]*T{m}, others...) {
+		for _, e := range o.snapshot() {
+			r.Set(e.k, e.v)
+		}
+	}
+
+	return r
+}
+
+// Name returns the name of the map type.
+func (m *hmap) Name() string {
+	return name
+}
+
+// Set associates the value v with the key k in the map m.
+func (m *hmap) Set(k, v cell.I) {
+	m.Lock()
+	defer m.Unlock()
+
+	if e := m.lookup(k); e != nil {
+		e.v = v
+
+		return
+	}
+
+	e := &entry{k: k, v: v}
+
+	h := hash(k)
+
+	m.entries = append(m.entries, e)
+	m.index[h] = append(m.index[h], e)
+}
+
+// String returns the text of the map m.
+func (m *hmap) String() string {
+	return m.string(common.String)
+}
+
+// Values returns a list of the map's values in insertion order.
+func (m *hmap) Values() cell.I {
+	return m.list(func(e *entry) cell.I {
+		return e.v
+	})
+}
+
+func (m *hmap) list(f func(*entry) cell.I) cell.I {
+	s := m.snapshot()
+
+	l := pair.Null
+	for i := len(s) - 1; i >= 0; i-- {
+		l = pair.Cons(f(&s[i]), l)
+	}
+
+	return l
+}
+
+func (m *hmap) lookup(k cell.I) *entry {
+	for _, e := range m.index[hash(k)] {
+		if e.k.Equal(k) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (m *hmap) string(toString func(cell.I) string) string {
+	var b strings.Builder
+
+	b.WriteString("(|")
+	b.WriteString(name)
+
+	for _, e := range m.snapshot() {
+		b.WriteString(" ")
+		b.WriteString(element(e.k, toString))
+		b.WriteString(" ")
+		b.WriteString(element(e.v, toString))
+	}
+
+	b.WriteString("|)")
+
+	return b.String()
+}
+
+func (m *hmap) snapshot() []entry {
+	m.RLock()
+	defer m.RUnlock()
+
+	s := make([]entry, len(m.entries))
+	for i, e := range m.entries {
+		s[i] = *e
+	}
+
+	return s
+}
+
+// The literal or text for a list omits the outermost parentheses.
+func element(c cell.I, toString func(cell.I) string) string {
+	if c == pair.Null {
+		return "()"
+	}
+
+	if pair.Is(c) && pair.Is(pair.Cdr(c)) {
+		return "(" + toString(c) + ")"
+	}
+
+	return toString(c)
+}
+
+// Keys with a value that can be compared as a string are hashed. All other
+// keys share a bucket and are compared one at a time.
+func hash(c cell.I) string {
+	switch {
+	case num.Is(c), status.Is(c):
+		return c.Name() + ":" + rational.Number(c).RatString()
+	case str.Is(c), sym.Is(c):
+		return c.Name() + ":" + common.String(c)
+	}
+
+	return ""
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t hmap
+
+	// The map type is a cell.
+	_ = cell.I(&t)
+
+	// The map type has a literal representation.
+	_ = literal.I(&t)
+
+	// The map type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The map type has a JSON representation.
+	_ = json.Marshaler(&t)
+
+	// The map type is a stringer.
+	_ = fmt.Stringer(&t)
+}
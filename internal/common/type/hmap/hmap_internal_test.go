@@ -0,0 +1,67 @@
+// Released under an MIT license. See LICENSE.
+
+package hmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+)
+
+func TestJSON(t *testing.T) {
+	m := New(
+		sym.New("a"), list.New(num.Int(1), sym.New("true"), str.New("<x>")),
+		str.New("b c"), New(),
+	)
+
+	want := `{"a":[1,true,"<x>"],"b c":{}}`
+	if got := fmt.Sprintf("%j", m); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	m := New(sym.New("b"), num.Int(1), str.New("b"), num.Int(2)).(*hmap)
+
+	m.Set(list.New(sym.New("x"), sym.New("y")), num.Int(3))
+	m.Set(num.New("2/2"), num.Int(4))
+	m.Set(sym.New("b"), num.Int(5))
+
+	if m.Length() != 4 {
+		t.Fatalf("expected 4 entries, got %d", m.Length())
+	}
+
+	v, ok := m.Get(num.Int(1))
+	if !ok || !v.Equal(num.Int(4)) {
+		t.Fatalf("expected equal numbers to be the same key")
+	}
+
+	v, ok = m.Get(list.New(sym.New("x"), sym.New("y")))
+	if !ok || !v.Equal(num.Int(3)) {
+		t.Fatalf("expected equal lists to be the same key")
+	}
+
+	if m.Literal() != "(|map b (|number 5|) $'b' (|number 2|) (x y) (|number 3|) (|number 1|) (|number 4|)|)" {
+		t.Fatalf("unexpected literal %s", m.Literal())
+	}
+
+	if m.String() != "(|map b 5 b 2 (x y) 3 1 4|)" {
+		t.Fatalf("unexpected string %s", m.String())
+	}
+}
+
+func TestDel(t *testing.T) {
+	m := New(sym.New("a"), num.Int(1), sym.New("b"), num.Int(2)).(*hmap)
+
+	if !m.Del(sym.New("a")) || m.Del(sym.New("a")) {
+		t.Fail()
+	}
+
+	if m.Literal() != "(|map b (|number 2|)|)" {
+		t.Fatalf("unexpected literal %s", m.Literal())
+	}
+}
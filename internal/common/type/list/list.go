@@ -6,6 +6,7 @@ package list
 
 import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 )
 
@@ -15,7 +16,7 @@ import (
 // The list must be non-circular.
 func Append(start cell.I, elements ...cell.I) cell.I {
 	if start == nil {
-		panic("cannot append to non-existent list")
+		panic(exception.New(exception.TypeError, "cannot append to non-existent list"))
 	}
 
 	if len(elements) == 0 {
@@ -72,7 +73,7 @@ func Join(lists ...cell.I) cell.I {
 	}
 
 	if start == nil {
-		panic("join must be passed at least one list")
+		panic(exception.New(exception.TypeError, "join must be passed at least one list"))
 	}
 
 	if start == pair.Null {
@@ -164,7 +165,7 @@ func Slice(list cell.I, start, end int64) cell.I {
 	}
 
 	if start < 0 {
-		panic("slice starts before first element")
+		panic(exception.New(exception.Error, "slice starts before first element"))
 	} else if start > length {
 		start = length
 	}
@@ -174,7 +175,7 @@ func Slice(list cell.I, start, end int64) cell.I {
 	}
 
 	if end < 0 {
-		panic("slice ends before first element")
+		panic(exception.New(exception.Error, "slice ends before first element"))
 	} else if end > length {
 		end = length
 	}
@@ -182,7 +183,7 @@ func Slice(list cell.I, start, end int64) cell.I {
 	end -= start
 
 	if end < 0 {
-		panic("end of slice before start")
+		panic(exception.New(exception.Error, "end of slice before start"))
 	} else if end == 0 {
 		return pair.Null
 	}
@@ -227,7 +228,7 @@ func Tail(list cell.I, index int64, dflt cell.I) cell.I {
 
 	if msg != "" {
 		if dflt == nil {
-			panic(msg)
+			panic(exception.New(exception.Error, msg))
 		} else {
 			return dflt
 		}
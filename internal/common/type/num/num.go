@@ -4,22 +4,46 @@
 package num
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 const name = "number"
 
-// T (num) wraps Go's big.Rat type.
-type T big.Rat
+// T (num) wraps Go's big.Rat type. Numbers are exact unless they are the
+// result of an approximate calculation. An inexact number is displayed as
+// a decimal and any result calculated from an inexact number is inexact.
+type T struct {
+	r       *big.Rat
+	inexact bool
+}
 
 type num = T
 
+// Float creates an inexact num from the float f.
+func Float(f float64) cell.I {
+	r := &big.Rat{}
+	if r.SetFloat64(f) == nil {
+		panic(exception.New(exception.Arithmetic, strconv.FormatFloat(f, 'g', -1, 64)+" is not a finite number"))
+	}
+
+	return Inexact(r)
+}
+
+// Inexact wraps the *big.Rat r as an inexact num.
+func Inexact(r *big.Rat) cell.I {
+	return &num{r: r, inexact: true}
+}
+
 // Int creates a num from the integer i.
 func Int(i int) cell.I {
 	return Rat(big.NewRat(int64(i), 1))
@@ -30,15 +54,25 @@ func New(s string) cell.I {
 	v := &big.Rat{}
 
 	if _, ok := v.SetString(s); !ok {
-		panic("'" + s + "' is not a valid number")
+		panic(exception.New(exception.Syntax, "'"+s+"' is not a valid number"))
 	}
 
 	return Rat(v)
 }
 
-// Rat creates wraps the *big.Rat r as a num.
+// NewInexact creates a new inexact num from a string.
+func NewInexact(s string) cell.I {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' is not a valid number"))
+	}
+
+	return Float(f)
+}
+
+// Rat wraps the *big.Rat r as a num.
 func Rat(r *big.Rat) cell.I {
-	return (*num)(r)
+	return &num{r: r}
 }
 
 // Bool returns the boolean value of the num n.
@@ -51,11 +85,36 @@ func (n *num) Equal(c cell.I) bool {
 	return Is(c) && n.Rat().Cmp(To(c).Rat()) == 0
 }
 
+// Format implements fmt.Formatter for the num n.
+func (n *num) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, n)
+}
+
+// Inexact returns true if the num n is the result of an approximation.
+func (n *num) Inexact() bool {
+	return n.inexact
+}
+
 // Literal returns the literal representation of the num n.
 func (n *num) Literal() string {
+	if n.inexact {
+		return "(|inexact " + n.String() + "|)"
+	}
+
 	return "(|" + name + " " + n.String() + "|)"
 }
 
+// MarshalJSON returns the JSON representation of the num n.
+func (n *num) MarshalJSON() ([]byte, error) {
+	if !n.inexact && n.r.IsInt() {
+		return []byte(n.r.RatString()), nil
+	}
+
+	f, _ := n.r.Float64()
+
+	return []byte(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
 // Name returns the type name for the num n.
 func (n *num) Name() string {
 	return name
@@ -63,12 +122,18 @@ func (n *num) Name() string {
 
 // Rat returns the value of the num n as a *big.Rat.
 func (n *num) Rat() *big.Rat {
-	return (*big.Rat)(n)
+	return n.r
 }
 
 // String returns the text of the num n.
 func (n *num) String() string {
-	return n.Rat().RatString()
+	if n.inexact {
+		f, _ := n.r.Float64()
+
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return n.r.RatString()
 }
 
 // A compiler-checked list of interfaces this type satisfies. Never called.
@@ -87,6 +152,12 @@ func implements() { //nolint:deadcode,unused
 	// The num type is a rational.
 	_ = rational.I(&t)
 
+	// The num type is a formatter.
+	_ = fmt.Formatter(&t)
+
 	// The num type is a stringer.
 	_ = fmt.Stringer(&t)
+
+	// The num type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
@@ -0,0 +1,64 @@
+// Released under an MIT license. See LICENSE.
+
+package num
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct {
+		format string
+		n      interface{}
+		want   string
+	}{
+		{"%d", New("42"), "42"},
+		{"%05d", New("-42"), "-0042"},
+		{"%x", New("255"), "ff"},
+		{"%.2f", New("1/3"), "0.33"},
+		{"%.3e", New("12345"), "1.234e+04"},
+		{"%g", Float(0.1), "0.1"},
+		{"%v", New("2/4"), "1/2"},
+		{"%6v", New("7"), "     7"},
+		{"%l", Float(0.5), "(|inexact 0.5|)"},
+		{"%j", New("1/2"), "0.5"},
+		{"%d", New("1/2"), "%!d(number=1/2)"},
+	} {
+		if got := fmt.Sprintf(c.format, c.n); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.format, c.want, got)
+		}
+	}
+}
+
+func TestInexact(t *testing.T) {
+	n := To(New("1/3"))
+	if n.Inexact() || n.String() != "1/3" {
+		t.Fatalf("expected exact 1/3, got %s", n.String())
+	}
+
+	f := To(Float(0.1))
+	if !f.Inexact() || f.String() != "0.1" {
+		t.Fatalf("expected inexact 0.1, got %s", f.String())
+	}
+
+	if f.Literal() != "(|inexact 0.1|)" {
+		t.Fatalf("unexpected literal %s", f.Literal())
+	}
+
+	if !To(NewInexact("0.1")).Equal(f) {
+		t.Fatalf("expected equal numbers")
+	}
+}
+
+func TestNotFinite(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for a number that is not finite")
+		}
+	}()
+
+	zero := 0.0
+
+	Float(1 / zero)
+}
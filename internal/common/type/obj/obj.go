@@ -4,9 +4,14 @@
 package obj
 
 import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/reference"
 	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 const name = "object"
@@ -40,7 +45,7 @@ func (o *obj) Clone() scope.I {
 
 // Define throws an error. Only public members of an obj can be added.
 func (o *obj) Define(k string, v cell.I) {
-	panic("private names cannot be added to object")
+	panic(exception.New(exception.Error, "private names cannot be added to object"))
 }
 
 // Equal returns true if c is obj as o.
@@ -53,11 +58,45 @@ func (o *obj) Expose() scope.I {
 	return o.wrapped
 }
 
+// Format implements fmt.Formatter for the obj o.
+func (o *obj) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, o)
+}
+
 // Lookup retrieves the reference associated with the public name k in the obj o.
 func (o *obj) Lookup(k string) reference.I {
 	return o.wrapped.Public().Get(k)
 }
 
+// MarshalJSON returns the JSON representation of the public members of
+// the obj o. Members without a JSON representation, like methods, are
+// skipped.
+func (o *obj) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+
+	for _, k := range o.Members() {
+		v, err := format.JSON(o.Lookup(k).Get())
+		if err != nil {
+			continue
+		}
+
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+
+		b = append(b, format.JSONString(k)...)
+		b = append(b, ':')
+		b = append(b, v...)
+	}
+
+	return append(b, '}'), nil
+}
+
+// Members returns the sorted public names in the obj o.
+func (o *obj) Members() []string {
+	return o.wrapped.Public().Keys()
+}
+
 // Name returns the type name for the obj o.
 func (o *obj) Name() string {
 	return name
@@ -77,4 +116,10 @@ func implements() { //nolint:deadcode,unused
 
 	// The obj type is a scope.
 	_ = scope.I(&t)
+
+	// The obj type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The obj type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
@@ -4,9 +4,12 @@
 package pair
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 )
@@ -27,6 +30,11 @@ type T struct {
 
 type pair = T
 
+// Bool returns false for the empty list and true otherwise.
+func (p *pair) Bool() bool {
+	return p != Null
+}
+
 // Equal returns true if c is a pair with elements that are equal to p's.
 func (p *pair) Equal(c cell.I) bool {
 	if p == Null && c == Null {
@@ -36,11 +44,70 @@ func (p *pair) Equal(c cell.I) bool {
 	return p.car.Equal(Car(c)) && p.cdr.Equal(Cdr(c))
 }
 
+// Format implements fmt.Formatter for the pair p. The literal form, %l,
+// of a list is enclosed in parentheses, as it is when written to a pipe.
+// When quoting for a shell, with %Q, each element is quoted separately.
+func (p *pair) Format(f fmt.State, verb rune) {
+	if p == Null || verb != 'l' && verb != 'Q' {
+		format.Cell(f, verb, p)
+
+		return
+	}
+
+	if verb == 'l' {
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), Cons(p, Null).(*pair).Literal())
+
+		return
+	}
+
+	q := []string{}
+
+	c := cell.I(p)
+	for ; Is(c) && c != Null; c = Cdr(c) {
+		q = append(q, fmt.Sprintf("%Q", Car(c)))
+	}
+
+	if c != Null {
+		q = append(q, fmt.Sprintf("%Q", c))
+	}
+
+	fmt.Fprintf(f, fmt.FormatString(f, 's'), strings.Join(q, " "))
+}
+
 // Literal returns the literal representation of the pair p.
 func (p *pair) Literal() string {
 	return p.string(literal.String)
 }
 
+// MarshalJSON returns the JSON representation of the list p. The empty
+// list is null.
+func (p *pair) MarshalJSON() ([]byte, error) {
+	if p == Null {
+		return []byte("null"), nil
+	}
+
+	b := []byte{'['}
+
+	for c := cell.I(p); c != Null; c = Cdr(c) {
+		if !Is(c) {
+			return nil, common.Error("an improper list does not have a JSON representation")
+		}
+
+		v, err := format.JSON(Car(c))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+
+		b = append(b, v...)
+	}
+
+	return append(b, ']'), nil
+}
+
 // Name returns the name for a pair type.
 func (p *pair) Name() string {
 	return name
@@ -169,8 +236,14 @@ func implements() { //nolint:deadcode,unused
 	// The pair type has a literal representation.
 	_ = literal.I(&t)
 
+	// The pair type is a formatter.
+	_ = fmt.Formatter(&t)
+
 	// The pair type is a stringer.
 	_ = fmt.Stringer(&t)
+
+	// The pair type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
 
 func init() { //nolint:gochecknoinits
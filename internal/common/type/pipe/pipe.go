@@ -6,6 +6,7 @@ package pipe
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"runtime"
@@ -13,9 +14,12 @@ import (
 	"sync"
 
 	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/conduit"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/type/blob"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
 	"github.com/michaelmacinnis/oh/internal/reader"
@@ -41,7 +45,7 @@ func New(r, w *os.File) cell.I {
 
 		r, w, err = os.Pipe()
 		if err != nil {
-			panic(err.Error())
+			panic(exception.Wrap(exception.IO, err))
 		}
 	}
 
@@ -71,6 +75,11 @@ func (p *pipe) Equal(c cell.I) bool {
 	return Is(c) && p == To(c)
 }
 
+// Format implements fmt.Formatter for the pipe p.
+func (p *pipe) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, p)
+}
+
 // Name returns the name of the pipe type.
 func (p *pipe) Name() string {
 	return name
@@ -100,7 +109,7 @@ func (p *pipe) Read() cell.I {
 	for ok {
 		c, err = r.Scan(s)
 		if err != nil {
-			panic(err.Error())
+			panic(exception.Wrap(exception.Syntax, err))
 		}
 
 		if c != nil {
@@ -117,6 +126,58 @@ func (p *pipe) Read() cell.I {
 	return c
 }
 
+// ReadAll reads everything remaining in the pipe as bytes. Like ReadBytes,
+// it returns () if there is nothing left to read.
+func (p *pipe) ReadAll() cell.I {
+	b := p.buffer()
+	if b == nil {
+		return pair.Null
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	bs, err := io.ReadAll(b)
+	if err != nil {
+		panic(exception.Wrap(exception.IO, err))
+	}
+
+	if len(bs) == 0 {
+		return pair.Null
+	}
+
+	return blob.New(bs)
+}
+
+// ReadBytes reads up to n bytes from the pipe. Fewer bytes are returned
+// only if the end of the input is reached first.
+func (p *pipe) ReadBytes(n int) cell.I {
+	if n < 0 {
+		panic(exception.New(exception.Error, "number of bytes must not be negative"))
+	}
+
+	b := p.buffer()
+	if b == nil {
+		return pair.Null
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	bs := make([]byte, n)
+
+	n, err := io.ReadFull(b, bs)
+	if errors.Is(err, io.EOF) {
+		return pair.Null
+	}
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		panic(exception.Wrap(exception.IO, err))
+	}
+
+	return blob.New(bs[:n])
+}
+
 // ReadLine reads a line from the pipe.
 func (p *pipe) ReadLine() cell.I {
 	b := p.buffer()
@@ -148,17 +209,41 @@ func (p *pipe) Write(c cell.I) {
 	defer p.RUnlock()
 
 	if p.w == nil {
-		panic("write to closed pipe")
+		panic(exception.New(exception.IO, "write to closed pipe"))
 	}
 
 	_, err := p.w.WriteString(literal.String(c))
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	_, err = p.w.WriteString("\n")
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
+	}
+}
+
+// WriteBytes writes the contents of a blob, or the string value of any
+// other cell, to the pipe as is.
+func (p *pipe) WriteBytes(c cell.I) {
+	// Yes, RLock. This is a write but doesn't change the pipe itself.
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.w == nil {
+		panic(exception.New(exception.IO, "write to closed pipe"))
+	}
+
+	var err error
+
+	if blob.Is(c) {
+		_, err = p.w.Write(blob.To(c).Bytes())
+	} else {
+		_, err = p.w.WriteString(common.String(c))
+	}
+
+	if err != nil {
+		panic(exception.Wrap(exception.IO, err))
 	}
 }
 
@@ -169,17 +254,17 @@ func (p *pipe) WriteLine(c cell.I) {
 	defer p.RUnlock()
 
 	if p.w == nil {
-		panic("write to closed pipe")
+		panic(exception.New(exception.IO, "write to closed pipe"))
 	}
 
 	_, err := p.w.WriteString(common.String(c))
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	_, err = p.w.WriteString("\n")
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 }
 
@@ -245,7 +330,7 @@ func (p *pipe) readerClosePipe() {
 	if p.r != nil {
 		err := p.r.Close()
 		if err != nil {
-			panic(err.Error())
+			panic(exception.Wrap(exception.IO, err))
 		}
 	}
 }
@@ -271,7 +356,7 @@ func (p *pipe) writerClosePipe() {
 
 	err := p.w.Close()
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 }
 
@@ -306,7 +391,7 @@ func line(b *bufio.Reader) (string, bool) {
 	}
 
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	return s, true
@@ -321,4 +406,7 @@ func implements() { //nolint:deadcode,unused
 
 	// The pipe type is a conduit.
 	_ = conduit.I(&t)
+
+	// The pipe type is a formatter.
+	_ = fmt.Formatter(&t)
 }
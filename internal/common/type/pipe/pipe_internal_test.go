@@ -5,8 +5,13 @@ package pipe
 import (
 	"testing"
 
+	"github.com/michaelmacinnis/oh/internal/common/type/blob"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
 )
 
 func TestWriteRead(t *testing.T) {
@@ -22,3 +27,57 @@ func TestWriteRead(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestWriteReadMap(t *testing.T) {
+	p := New(nil, nil).(*pipe)
+
+	sent := hmap.New(
+		sym.New("a"), num.Int(1),
+		list.New(sym.New("b"), str.New("c")), pair.Null,
+		str.New("d e"), hmap.New(sym.New("f"), sym.New("g")),
+	)
+
+	p.Write(sent)
+
+	received := pair.Car(p.Read())
+
+	if !received.Equal(sent) {
+		t.Fail()
+	}
+}
+
+func TestWriteBytesReadBytes(t *testing.T) {
+	p := New(nil, nil).(*pipe)
+
+	sent := blob.New([]byte{0, 1, '\n', 255})
+
+	p.WriteBytes(sent)
+	p.WriterClose()
+
+	if received := p.ReadBytes(3); !received.Equal(blob.New([]byte{0, 1, '\n'})) {
+		t.Fail()
+	}
+
+	if received := p.ReadBytes(3); !received.Equal(blob.New([]byte{255})) {
+		t.Fail()
+	}
+
+	if received := p.ReadBytes(3); received != pair.Null {
+		t.Fail()
+	}
+}
+
+func TestWriteLineReadAll(t *testing.T) {
+	p := New(nil, nil).(*pipe)
+
+	p.WriteLine(str.New("hello"))
+	p.WriterClose()
+
+	if received := p.ReadAll(); !received.Equal(blob.New([]byte("hello\n"))) {
+		t.Fail()
+	}
+
+	if received := p.ReadAll(); received != pair.Null {
+		t.Fail()
+	}
+}
@@ -0,0 +1,125 @@
+// Released under an MIT license. See LICENSE.
+
+// Package pipeline provides oh's pipeline result type.
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+)
+
+const name = "pipeline"
+
+// T (pipeline) holds the result of each stage of a pipeline. After a
+// pipeline runs, its T is bound to pipestatus in the calling scope.
+type T struct {
+	fail   bool
+	stages []cell.I
+}
+
+type pipeline = T
+
+// New creates a new pipeline result from the results of each stage, in
+// order. If fail is true, the pipeline's boolean value reflects the
+// rightmost stage with a false value rather than only the last stage.
+func New(fail bool, stages ...cell.I) cell.I {
+	if len(stages) == 0 {
+		panic(exception.New(exception.Error, "a pipeline must have at least one stage"))
+	}
+
+	return &pipeline{fail: fail, stages: stages}
+}
+
+// Bool returns the boolean value of the pipeline p.
+func (p *pipeline) Bool() bool {
+	return boolean.Value(p.Result())
+}
+
+// Equal returns true if c is the same pipeline as p.
+func (p *pipeline) Equal(c cell.I) bool {
+	return Is(c) && p == To(c)
+}
+
+// Format implements fmt.Formatter for the pipeline p.
+func (p *pipeline) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, p)
+}
+
+// Length returns the number of stages in the pipeline p.
+func (p *pipeline) Length() int {
+	return len(p.stages)
+}
+
+// Name returns the name of the pipeline type.
+func (p *pipeline) Name() string {
+	return name
+}
+
+// Result returns the result that determines the pipeline's value. This is
+// the result of the last stage or, if the pipeline fails when any stage
+// fails, the result of the rightmost stage with a false value.
+func (p *pipeline) Result() cell.I {
+	last := p.stages[len(p.stages)-1]
+
+	if p.fail {
+		for i := len(p.stages) - 1; i >= 0; i-- {
+			if !boolean.Value(p.stages[i]) {
+				return p.stages[i]
+			}
+		}
+	}
+
+	return last
+}
+
+// Stage returns the result of the stage at index i.
+func (p *pipeline) Stage(i int) cell.I {
+	if i < 0 {
+		i += len(p.stages)
+	}
+
+	if i < 0 || i >= len(p.stages) {
+		panic(exception.New(exception.Error, "pipeline has no stage "+fmt.Sprint(i)))
+	}
+
+	return p.stages[i]
+}
+
+// Stages returns a list of the results of each stage.
+func (p *pipeline) Stages() cell.I {
+	return list.New(p.stages...)
+}
+
+// String returns the text of the result that determines the pipeline's value.
+func (p *pipeline) String() string {
+	r := p.Result()
+
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return literal.String(r)
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t pipeline
+
+	// The pipeline type has a boolean value.
+	_ = boolean.I(&t)
+
+	// The pipeline type is a cell.
+	_ = cell.I(&t)
+
+	// The pipeline type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The pipeline type is a stringer.
+	_ = fmt.Stringer(&t)
+}
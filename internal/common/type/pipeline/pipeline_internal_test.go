@@ -0,0 +1,29 @@
+// Released under an MIT license. See LICENSE.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/type/status"
+)
+
+func TestLastStage(t *testing.T) {
+	p := New(false, status.Int(1), status.Int(0)).(*pipeline)
+
+	if !p.Bool() || p.String() != "0" {
+		t.Fail()
+	}
+}
+
+func TestPipefail(t *testing.T) {
+	p := New(true, status.Int(2), status.Int(3), status.Int(0)).(*pipeline)
+
+	if p.Bool() || p.String() != "3" {
+		t.Fail()
+	}
+
+	if !p.Stage(-1).Equal(status.Int(0)) || !p.Stage(0).Equal(status.Int(2)) {
+		t.Fail()
+	}
+}
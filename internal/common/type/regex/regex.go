@@ -0,0 +1,114 @@
+// Released under an MIT license. See LICENSE.
+
+// Package regex provides oh's compiled regular expression type.
+package regex
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
+
+const (
+	name = "regex"
+
+	// Patterns compiled implicitly are remembered up to this limit.
+	limit = 256
+)
+
+// T (regex) is a compiled regular expression.
+type T struct {
+	*regexp.Regexp
+}
+
+type regex = T
+
+//nolint:gochecknoglobals
+var cache = struct {
+	sync.Mutex
+	m map[string]*regex
+}{m: map[string]*regex{}}
+
+// New compiles the pattern s and returns a regex or panics if s is not valid.
+func New(s string) cell.I {
+	return compile(s)
+}
+
+// Compile returns c if it is a regex; Otherwise c is converted to a string
+// and compiled. Compiled patterns are cached so that repeatedly using the
+// same pattern, as a string, does not repeatedly compile it.
+func Compile(c cell.I) *T {
+	if Is(c) {
+		return To(c)
+	}
+
+	s := common.String(c)
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	r, ok := cache.m[s]
+	if !ok {
+		r = compile(s)
+
+		if len(cache.m) >= limit {
+			cache.m = map[string]*regex{}
+		}
+
+		cache.m[s] = r
+	}
+
+	return r
+}
+
+// Equal returns true if c is a regex with the same pattern as r.
+func (r *regex) Equal(c cell.I) bool {
+	return Is(c) && r.String() == To(c).String()
+}
+
+// Format implements fmt.Formatter for the regex r.
+func (r *regex) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, r)
+}
+
+// MarshalJSON returns the JSON representation of the pattern for r.
+func (r *regex) MarshalJSON() ([]byte, error) {
+	return format.JSONString(r.String()), nil
+}
+
+// Name returns the name of the regex type.
+func (r *regex) Name() string {
+	return name
+}
+
+func compile(s string) *regex {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		panic(exception.Wrap(exception.Syntax, err))
+	}
+
+	return &regex{re}
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t regex
+
+	// The regex type is a cell.
+	_ = cell.I(&t)
+
+	// The regex type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The regex type is a stringer.
+	_ = fmt.Stringer(&t)
+
+	// The regex type has a JSON representation.
+	_ = json.Marshaler(&t)
+}
@@ -0,0 +1,37 @@
+// Released under an MIT license. See LICENSE.
+
+package regex
+
+import (
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+)
+
+func TestCompile(t *testing.T) {
+	a := Compile(str.New(`\d+`))
+	b := Compile(str.New(`\d+`))
+
+	if a != b {
+		t.Fatalf("expected the same pattern to be compiled once")
+	}
+
+	if Compile(a) != a {
+		t.Fatalf("expected a compiled pattern to be used as is")
+	}
+
+	c := New(`\d+`)
+	if c == a || !c.Equal(a) {
+		t.Fatalf("expected a new, equal, regex")
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected an invalid pattern to panic")
+		}
+	}()
+
+	Compile(str.New(`(`))
+}
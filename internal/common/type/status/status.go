@@ -4,13 +4,16 @@
 package status
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 const name = "status"
@@ -30,7 +33,7 @@ func New(s string) cell.I {
 	v := &big.Rat{}
 
 	if _, ok := v.SetString(s); !ok {
-		panic("'" + s + "' is not a valid number")
+		panic(exception.New(exception.Syntax, "'"+s+"' is not a valid number"))
 	}
 
 	return Rat(v)
@@ -51,11 +54,21 @@ func (s *status) Equal(c cell.I) bool {
 	return Is(c) && s.Rat().Cmp(To(c).Rat()) == 0
 }
 
+// Format implements fmt.Formatter for the status s.
+func (s *status) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, s)
+}
+
 // Literal returns the literal representation of the status s.
 func (s *status) Literal() string {
 	return "(|" + name + " " + s.String() + "|)"
 }
 
+// MarshalJSON returns the JSON representation of the status s.
+func (s *status) MarshalJSON() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // Rat returns the value of the status s as a *big.Rat.
 func (s *status) Rat() *big.Rat {
 	return (*big.Rat)(s)
@@ -87,6 +100,12 @@ func implements() { //nolint:deadcode,unused
 	// The status type is a rational.
 	_ = rational.I(&t)
 
+	// The status type is a formatter.
+	_ = fmt.Formatter(&t)
+
 	// The status type is a stringer.
 	_ = fmt.Stringer(&t)
+
+	// The status type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
@@ -4,9 +4,11 @@
 package str
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/michaelmacinnis/adapted"
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 )
@@ -30,11 +32,21 @@ func (s *str) Equal(c cell.I) bool {
 	return Is(c) && s.String() == To(c).String()
 }
 
+// Format implements fmt.Formatter for the str s.
+func (s *str) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, s)
+}
+
 // Literal returns the literal representation of the str s.
 func (s *str) Literal() string {
 	return adapted.CanonicalString(string(*s))
 }
 
+// MarshalJSON returns the JSON representation of the str s.
+func (s *str) MarshalJSON() ([]byte, error) {
+	return format.JSONString(s.String()), nil
+}
+
 // Name returns the name of the str type.
 func (s *str) Name() string {
 	return name
@@ -55,6 +67,12 @@ func implements() { //nolint:deadcode,unused
 	// The str type has a literal representation.
 	_ = literal.I(&t)
 
+	// The str type is a formatter.
+	_ = fmt.Formatter(&t)
+
 	// The str type is a stringer.
 	_ = fmt.Stringer(&t)
+
+	// The str type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
@@ -4,6 +4,7 @@ package sym
 
 import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 )
 
 // Is returns true if c is a *T or *Plus.
@@ -25,5 +26,5 @@ func To(c cell.I) *T {
 		return t.sym
 	}
 
-	panic("not a " + name)
+	panic(exception.New(exception.TypeError, "not a "+name))
 }
@@ -4,11 +4,13 @@
 package sym
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"sync"
 
 	"github.com/michaelmacinnis/adapted"
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
@@ -38,11 +40,30 @@ func (s *sym) Equal(c cell.I) bool {
 	return Is(c) && s.String() == To(c).String()
 }
 
+// Format implements fmt.Formatter for the sym s.
+func (s *sym) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, s)
+}
+
 // Literal returns the literal representation of the sym s.
 func (s *sym) Literal() string {
 	return repr(string(*s))
 }
 
+// MarshalJSON returns the JSON representation of the sym s. The symbol
+// true is true and symbols that are numbers are numbers.
+func (s *sym) MarshalJSON() ([]byte, error) {
+	if s.String() == "true" {
+		return []byte("true"), nil
+	}
+
+	if n, ok := number(s.String()); ok {
+		return n.(json.Marshaler).MarshalJSON()
+	}
+
+	return format.JSONString(s.String()), nil
+}
+
 // Name returns the type name for the sym s.
 func (s *sym) Name() string {
 	return name
@@ -85,6 +106,16 @@ func meta(s string) string {
 	return "(|" + name + " " + s + "|)"
 }
 
+func number(s string) (n cell.I, ok bool) {
+	defer func() {
+		if recover() != nil {
+			n, ok = nil, false
+		}
+	}()
+
+	return num.New(s), true
+}
+
 func repr(s string) string {
 	q := adapted.CanonicalString(s)
 
@@ -149,6 +180,12 @@ func implements() { //nolint:deadcode,unused
 	// The sym type has a literal representation.
 	_ = literal.I(&t)
 
+	// The sym type is a formatter.
+	_ = fmt.Formatter(&t)
+
 	// The sym type is a stringer.
 	_ = fmt.Stringer(&t)
+
+	// The sym type has a JSON representation.
+	_ = json.Marshaler(&t)
 }
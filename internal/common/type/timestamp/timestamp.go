@@ -0,0 +1,107 @@
+// Released under an MIT license. See LICENSE.
+
+// Package timestamp provides oh's timestamp type.
+package timestamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
+
+const name = "timestamp"
+
+// T (timestamp) is an instant in time with a location.
+type T struct {
+	t time.Time
+}
+
+type timestamp = T
+
+// At creates a timestamp for the time t.
+func At(t time.Time) cell.I {
+	return &timestamp{t}
+}
+
+// New creates a timestamp from its RFC 3339 text representation.
+func New(s string) cell.I {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' is not a valid timestamp"))
+	}
+
+	return At(t)
+}
+
+// Compare returns -1, 0, or +1 if the timestamp ts is before, the same
+// instant as, or after the timestamp u.
+func (ts *timestamp) Compare(u *T) int {
+	switch {
+	case ts.t.Before(u.t):
+		return -1
+	case ts.t.After(u.t):
+		return 1
+	}
+
+	return 0
+}
+
+// Equal returns true if c is a timestamp for the same instant as ts.
+func (ts *timestamp) Equal(c cell.I) bool {
+	return Is(c) && ts.t.Equal(To(c).t)
+}
+
+// Format implements fmt.Formatter for the timestamp ts.
+func (ts *timestamp) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, ts)
+}
+
+// Literal returns the literal representation of the timestamp ts.
+func (ts *timestamp) Literal() string {
+	return "(|" + name + " " + ts.String() + "|)"
+}
+
+// MarshalJSON returns the JSON representation of the timestamp ts.
+func (ts *timestamp) MarshalJSON() ([]byte, error) {
+	return format.JSONString(ts.String()), nil
+}
+
+// Name returns the name of the timestamp type.
+func (ts *timestamp) Name() string {
+	return name
+}
+
+// String returns the RFC 3339 text of the timestamp ts.
+func (ts *timestamp) String() string {
+	return ts.t.Format(time.RFC3339Nano)
+}
+
+// Time returns the value of the timestamp ts as a time.Time.
+func (ts *timestamp) Time() time.Time {
+	return ts.t
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t timestamp
+
+	// The timestamp type is a cell.
+	_ = cell.I(&t)
+
+	// The timestamp type has a literal representation.
+	_ = literal.I(&t)
+
+	// The timestamp type is a formatter.
+	_ = fmt.Formatter(&t)
+
+	// The timestamp type is a stringer.
+	_ = fmt.Stringer(&t)
+
+	// The timestamp type has a JSON representation.
+	_ = json.Marshaler(&t)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 )
@@ -19,7 +20,7 @@ func Variadic(actual cell.I, min, max int) ([]cell.I, cell.I) {
 		if actual == pair.Null {
 			if i < min {
 				s := Count(min, "argument", "s")
-				panic(fmt.Sprintf("expected %s, passed %d", s, i))
+				panic(exception.New(exception.TypeError, fmt.Sprintf("expected %s, passed %d", s, i)))
 			}
 
 			break
@@ -40,7 +41,7 @@ func Fixed(actual cell.I, min, max int) []cell.I {
 		s := Count(max, "argument", "s")
 		n := int(list.Length(actual))
 
-		panic(fmt.Sprintf("expected %s, passed %d", s, n))
+		panic(exception.New(exception.TypeError, fmt.Sprintf("expected %s, passed %d", s, n)))
 	}
 
 	return expected
@@ -5,9 +5,20 @@ package boot
 
 import _ "embed" // Blank import required by embed.
 
+//go:generate go run generate.go
+
 //go:embed boot.oh
 var script string //nolint:gochecknoglobals
 
+//go:embed boot.ohc
+var compiled []byte //nolint:gochecknoglobals
+
+// Compiled returns the boot script for oh in the binary form produced by
+// reader.Encode. It is generated from boot.oh by go generate.
+func Compiled() []byte {
+	return compiled
+}
+
 // Script returns the boot script for oh.
 func Script() string { //nolint:funlen
 	return script
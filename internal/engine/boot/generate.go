@@ -0,0 +1,38 @@
+// Released under an MIT license. See LICENSE.
+
+//go:build ignore
+// +build ignore
+
+// Generate parses boot.oh and writes the result, in binary form, to
+// boot.ohc so that oh does not have to parse its boot script on start-up.
+package main
+
+import (
+	"os"
+
+	"github.com/michaelmacinnis/oh/internal/reader"
+)
+
+func main() {
+	b, err := os.ReadFile("boot.oh")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	text := string(b)
+
+	cs, err := reader.Commands("boot.oh", text)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	b, err = reader.Encode(text, cs)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = os.WriteFile("boot.ohc", b, 0o644) //nolint:gosec
+	if err != nil {
+		panic(err.Error())
+	}
+}
@@ -7,12 +7,20 @@ import (
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
 )
 
+// Results are exact unless an argument is inexact.
+
 func add(args cell.I) cell.I {
+	if temporal(args) {
+		return addTime(args)
+	}
+
+	all := args
 	sum := &big.Rat{}
 
 	for args != pair.Null {
@@ -21,22 +29,28 @@ func add(args cell.I) cell.I {
 		args = pair.Cdr(args)
 	}
 
-	return num.Rat(sum)
+	return result(sum, all)
 }
 
 func div(args cell.I) cell.I {
+	all := args
 	v, args := validate.Variadic(args, 1, 1)
 
 	quotient := &big.Rat{}
 	quotient.Set(rational.Number(v[0]))
 
 	for args != pair.Null {
-		quotient.Quo(quotient, rational.Number(pair.Car(args)))
+		divisor := rational.Number(pair.Car(args))
+		if divisor.Sign() == 0 {
+			panic(exception.New(exception.Arithmetic, "division by zero"))
+		}
+
+		quotient.Quo(quotient, divisor)
 
 		args = pair.Cdr(args)
 	}
 
-	return num.Rat(quotient)
+	return result(quotient, all)
 }
 
 func mod(args cell.I) cell.I {
@@ -46,11 +60,15 @@ func mod(args cell.I) cell.I {
 	divisor := rational.Number(v[1])
 
 	if !remainder.IsInt() {
-		panic("dividend must be an integer")
+		panic(exception.New(exception.TypeError, "dividend must be an integer"))
 	}
 
 	if !divisor.IsInt() {
-		panic("divisor must be an integer")
+		panic(exception.New(exception.TypeError, "divisor must be an integer"))
+	}
+
+	if divisor.Sign() == 0 {
+		panic(exception.New(exception.Arithmetic, "division by zero"))
 	}
 
 	dividend := &big.Int{}
@@ -61,10 +79,11 @@ func mod(args cell.I) cell.I {
 	remainder = &big.Rat{}
 	remainder.SetInt(dividend)
 
-	return num.Rat(remainder)
+	return result(remainder, args)
 }
 
 func mul(args cell.I) cell.I {
+	all := args
 	v, args := validate.Variadic(args, 1, 1)
 
 	product := &big.Rat{}
@@ -76,10 +95,15 @@ func mul(args cell.I) cell.I {
 		args = pair.Cdr(args)
 	}
 
-	return num.Rat(product)
+	return result(product, all)
 }
 
 func sub(args cell.I) cell.I {
+	if temporal(args) {
+		return subTime(args)
+	}
+
+	all := args
 	v, args := validate.Variadic(args, 1, 1)
 
 	difference := &big.Rat{}
@@ -91,5 +115,23 @@ func sub(args cell.I) cell.I {
 		args = pair.Cdr(args)
 	}
 
-	return num.Rat(difference)
+	return result(difference, all)
+}
+
+func inexact(args cell.I) bool {
+	for ; args != pair.Null; args = pair.Cdr(args) {
+		if n, ok := pair.Car(args).(*num.T); ok && n.Inexact() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func result(r *big.Rat, args cell.I) cell.I {
+	if inexact(args) {
+		return num.Inexact(r)
+	}
+
+	return num.Rat(r)
 }
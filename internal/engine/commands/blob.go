@@ -0,0 +1,141 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/blob"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// BytesFunctions returns a mapping of names to bytes methods.
+//
+// Anything other than bytes passed to these methods is converted to a
+// string and the UTF-8 encoding of that string is used.
+func BytesFunctions() map[string]func(cell.I) cell.I {
+	return map[string]func(cell.I) cell.I{
+		"base64":      toBase64,
+		"concat":      concat,
+		"decode":      decode,
+		"encode":      encode,
+		"from-base64": fromBase64,
+		"from-hex":    fromHex,
+		"hex":         toHex,
+		"length":      blength,
+		"slice":       bslice,
+		"utf8?":       isUTF8,
+	}
+}
+
+func blength(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Int(len(octets(v[0])))
+}
+
+func bslice(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 3)
+
+	b := octets(v[0])
+
+	start, end := bounds(v, int64(len(b)))
+
+	return blob.New(b[start:end])
+}
+
+func concat(args cell.I) cell.I {
+	b := []byte{}
+
+	for ; args != pair.Null; args = pair.Cdr(args) {
+		b = append(b, octets(pair.Car(args))...)
+	}
+
+	return blob.New(b)
+}
+
+// Decode returns the string encoded, as UTF-8, by bytes. If the bytes are
+// not valid UTF-8, the offset of the first invalid byte is reported.
+func decode(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	b := octets(v[0])
+
+	for i := 0; i < len(b); {
+		r, n := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && n < 2 { //nolint:gomnd
+			panic(exception.New(exception.Syntax, "invalid UTF-8 at byte "+strconv.Itoa(i)))
+		}
+
+		i += n
+	}
+
+	return str.New(string(b))
+}
+
+func encode(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return blob.New([]byte(common.String(v[0])))
+}
+
+func fromBase64(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	s := common.String(v[0])
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' is not valid base64"))
+	}
+
+	return blob.New(b)
+}
+
+func fromHex(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return blob.Hex(common.String(v[0]))
+}
+
+func isBytes(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(blob.Is(v[0]))
+}
+
+func isUTF8(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(utf8.Valid(octets(v[0])))
+}
+
+func octets(c cell.I) []byte {
+	if blob.Is(c) {
+		return blob.To(c).Bytes()
+	}
+
+	return []byte(common.String(c))
+}
+
+func toBase64(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return str.New(base64.StdEncoding.EncodeToString(octets(v[0])))
+}
+
+func toHex(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return str.New(hex.EncodeToString(octets(v[0])))
+}
@@ -9,41 +9,53 @@ import (
 // Functions returns a mapping of names to 'methods' that do not reference self.
 func Functions() map[string]func(cell.I) cell.I {
 	return map[string]func(cell.I) cell.I{
-		"add":       add,
-		"bool":      makeBool,
-		"chan":      makeChan,
-		"chan?":     isChan,
-		"cons":      cons,
-		"cons?":     isCons,
-		"debug":     debug,
-		"div":       div,
-		"equal?":    equal,
-		"ge?":       ge,
-		"gt?":       gt,
-		"le?":       le,
-		"lt?":       lt,
-		"match":     match,
-		"mend":      mend,
-		"mod":       mod,
-		"mul":       mul,
-		"not":       not,
-		"null?":     isNull,
-		"number":    number,
-		"number?":   isNumber,
-		"object?":   isObject,
-		"open":      open,
-		"pipe":      makePipe,
-		"pipe?":     isPipe,
-		"random":    random,
-		"rend":      rend,
-		"sprintf":   sprintf,
-		"status":    makeStatus,
-		"string":    makeString,
-		"string?":   isString,
-		"symbol":    makeSymbol,
-		"symbol?":   isSymbol,
-		"sub":       sub,
-		"temp-fifo": tempfifo,
-		"umask":     umask,
+		"add":             add,
+		"bool":            makeBool,
+		"bytes?":          isBytes,
+		"chan":            makeChan,
+		"chan?":           isChan,
+		"cons":            cons,
+		"cons?":           isCons,
+		"debug":           debug,
+		"div":             div,
+		"duration?":       isDuration,
+		"equal?":          equal,
+		"exception?":      isException,
+		"ge?":             ge,
+		"gt?":             gt,
+		"le?":             le,
+		"lt?":             lt,
+		"map":             makeMap,
+		"map?":            isMap,
+		"match":           match,
+		"mend":            mend,
+		"mod":             mod,
+		"mul":             mul,
+		"not":             not,
+		"null?":           isNull,
+		"number":          number,
+		"number?":         isNumber,
+		"object?":         isObject,
+		"open":            open,
+		"pipe":            makePipe,
+		"pipe?":           isPipe,
+		"pipeline":        makePipeline,
+		"pipeline?":       isPipeline,
+		"random":          random,
+		"range":           numbers,
+		"regex?":          isRegex,
+		"rend":            rend,
+		"sandbox-config":  sandboxConfig,
+		"sandbox-release": sandboxRelease,
+		"sprintf":         sprintf,
+		"status":          makeStatus,
+		"string":          makeString,
+		"string?":         isString,
+		"symbol":          makeSymbol,
+		"symbol?":         isSymbol,
+		"sub":             sub,
+		"temp-fifo":       tempfifo,
+		"timestamp?":      isTimestamp,
+		"umask":           umask,
 	}
 }
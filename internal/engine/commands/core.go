@@ -13,6 +13,7 @@ import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
 	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
 	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/status"
@@ -50,7 +51,7 @@ func match(args cell.I) cell.I {
 
 	ok, err := adapted.Match(common.String(v[0]), common.String(v[1]))
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.Syntax, err))
 	}
 
 	return create.Bool(ok)
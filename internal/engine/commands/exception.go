@@ -0,0 +1,90 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// ExceptionMethods returns a mapping of names to exception methods.
+func ExceptionMethods() map[string]func(cell.I, cell.I) cell.I {
+	return map[string]func(cell.I, cell.I) cell.I{
+		"cause":    exceptionCause,
+		"errno":    exceptionErrno,
+		"kind":     exceptionKind,
+		"location": exceptionLocation,
+		"message":  exceptionMessage,
+		"trace":    exceptionTrace,
+	}
+}
+
+func exceptionCause(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return orNull(exception.To(s).Cause())
+}
+
+// An errno of () means the exception was not caused by a system call.
+func exceptionErrno(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	n := exception.To(s).Errno()
+	if n == 0 {
+		return pair.Null
+	}
+
+	return num.Int(n)
+}
+
+func exceptionKind(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return sym.New(exception.To(s).Kind())
+}
+
+// ExceptionLocation returns the source, line and column where an exception
+// was raised as a list, or () if the location is unknown.
+func exceptionLocation(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	l := exception.To(s).Loc()
+	if l == nil {
+		return pair.Null
+	}
+
+	return list.New(str.New(l.Name), num.Int(l.Line), num.Int(l.Char))
+}
+
+func exceptionMessage(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return str.New(exception.To(s).Message())
+}
+
+func exceptionTrace(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return orNull(exception.To(s).Trace())
+}
+
+func isException(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(exception.Is(v[0]))
+}
+
+func orNull(c cell.I) cell.I {
+	if c == nil {
+		return pair.Null
+	}
+
+	return c
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/michaelmacinnis/adapted"
 	"github.com/michaelmacinnis/oh/internal/common"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/pipe"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
 )
 
 func open(args cell.I) cell.I {
@@ -51,9 +53,11 @@ func open(args cell.I) cell.I {
 		flags |= os.O_WRONLY
 	}
 
+	policy.Open(path, read, write)
+
 	f, err := os.OpenFile(path, flags, 0o666)
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	r := f
@@ -74,7 +78,7 @@ func tempfifo(args cell.I) cell.I {
 
 	name, err := adapted.TempFifo("fifo-")
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	return sym.New(name)
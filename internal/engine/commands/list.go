@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"math/big"
+
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
@@ -14,14 +19,18 @@ func ListMethods() map[string]func(cell.I, cell.I) cell.I {
 	return map[string]func(cell.I, cell.I) cell.I{
 		"append":   appendMethod,
 		"extend":   extend,
+		"flatten":  flatten,
 		"get":      get,
 		"head":     head,
+		"index-of": indexOf,
 		"length":   length,
 		"reverse":  reverse,
 		"set-head": setHead,
 		"set-tail": setTail,
 		"slice":    slice,
 		"tail":     tail,
+		"uniq":     uniq,
+		"zip":      zip,
 	}
 }
 
@@ -41,6 +50,32 @@ func extend(s, args cell.I) cell.I {
 	return list.Join(self, v[0])
 }
 
+// Flatten replaces each element that is a list with its elements. Without
+// a depth, nested lists are flattened completely.
+func flatten(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 0, 1)
+
+	depth := int64(-1)
+	if len(v) == 1 {
+		depth = integer.Value(v[0])
+	}
+
+	return list.New(flattened(nil, pair.To(s), depth)...)
+}
+
+func flattened(acc []cell.I, l cell.I, depth int64) []cell.I {
+	for ; l != pair.Null; l = pair.Cdr(l) {
+		e := pair.Car(l)
+		if depth != 0 && pair.Is(e) {
+			acc = flattened(acc, e, depth-1)
+		} else {
+			acc = append(acc, e)
+		}
+	}
+
+	return acc
+}
+
 func get(s, args cell.I) cell.I {
 	v, args := validate.Variadic(args, 0, 1)
 
@@ -63,12 +98,55 @@ func head(s, _ cell.I) cell.I {
 	return pair.Car(pair.To(s))
 }
 
+func indexOf(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	i := 0
+	for l := cell.I(pair.To(s)); l != pair.Null; l = pair.Cdr(l) {
+		if v[0].Equal(pair.Car(l)) {
+			return num.Int(i)
+		}
+		i++
+	}
+
+	return pair.Null
+}
+
 func length(s, args cell.I) cell.I {
 	validate.Fixed(args, 0, 0)
 
 	return num.Int(int(list.Length(pair.To(s))))
 }
 
+// Numbers returns a list of numbers from start up to, but not including,
+// end. By default the list starts at 0 and each number is 1 more than the
+// previous number.
+func numbers(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 3)
+
+	start, end, step := big.NewRat(0, 1), rational.Number(v[0]), big.NewRat(1, 1)
+	if len(v) > 1 {
+		start, end = rational.Number(v[0]), rational.Number(v[1])
+	}
+
+	if len(v) > 2 { //nolint:gomnd
+		step = rational.Number(v[2])
+	}
+
+	sign := step.Sign()
+	if sign == 0 {
+		panic(exception.New(exception.Error, "step must not be zero"))
+	}
+
+	l := []cell.I{}
+
+	for n := new(big.Rat).Set(start); n.Cmp(end)*sign < 0; n = new(big.Rat).Add(n, step) {
+		l = append(l, num.Rat(n))
+	}
+
+	return list.New(l...)
+}
+
 func reverse(s, args cell.I) cell.I {
 	validate.Fixed(args, 0, 0)
 
@@ -105,3 +183,44 @@ func slice(s, args cell.I) cell.I {
 func tail(s, _ cell.I) cell.I {
 	return pair.Cdr(pair.To(s))
 }
+
+// Uniq removes duplicates, keeping the first occurrence of each element.
+func uniq(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	seen := hmap.To(hmap.New())
+	unique := []cell.I{}
+
+	for l := cell.I(pair.To(s)); l != pair.Null; l = pair.Cdr(l) {
+		e := pair.Car(l)
+		if _, ok := seen.Get(e); !ok {
+			seen.Set(e, pair.Null)
+			unique = append(unique, e)
+		}
+	}
+
+	return list.New(unique...)
+}
+
+// Zip combines corresponding elements of this list and each of the lists
+// passed as arguments. The result is as long as the shortest list.
+func zip(s, args cell.I) cell.I {
+	lists := append([]cell.I{pair.To(s)}, list.Array(args)...)
+
+	zipped := []cell.I{}
+
+	for {
+		tuple := make([]cell.I, len(lists))
+
+		for i, l := range lists {
+			if l == pair.Null {
+				return list.New(zipped...)
+			}
+
+			tuple[i] = pair.Car(l)
+			lists[i] = pair.Cdr(l)
+		}
+
+		zipped = append(zipped, list.New(tuple...))
+	}
+}
@@ -0,0 +1,108 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// MapMethods returns a mapping of names to methods for maps.
+func MapMethods() map[string]func(cell.I, cell.I) cell.I {
+	return map[string]func(cell.I, cell.I) cell.I{
+		"del":    mapDel,
+		"get":    mapGet,
+		"has":    mapHas,
+		"items":  mapItems,
+		"keys":   mapKeys,
+		"length": mapLength,
+		"merge":  mapMerge,
+		"set":    mapSet,
+		"values": mapValues,
+	}
+}
+
+func isMap(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(hmap.Is(v[0]))
+}
+
+func makeMap(args cell.I) cell.I {
+	return hmap.New(list.Array(args)...)
+}
+
+func mapDel(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(hmap.To(s).Del(v[0]))
+}
+
+func mapGet(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 2)
+
+	r, ok := hmap.To(s).Get(v[0])
+	if ok {
+		return r
+	}
+
+	if len(v) > 1 {
+		return v[1]
+	}
+
+	return pair.Null
+}
+
+func mapHas(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	_, ok := hmap.To(s).Get(v[0])
+
+	return create.Bool(ok)
+}
+
+func mapItems(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return hmap.To(s).Items()
+}
+
+func mapKeys(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return hmap.To(s).Keys()
+}
+
+func mapLength(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return num.Int(hmap.To(s).Length())
+}
+
+func mapMerge(s, args cell.I) cell.I {
+	others := []*hmap.T{}
+	for _, c := range list.Array(args) {
+		others = append(others, hmap.To(c))
+	}
+
+	return hmap.To(s).Merge(others...)
+}
+
+func mapSet(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	hmap.To(s).Set(v[0], v[1])
+
+	return v[1]
+}
+
+func mapValues(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return hmap.To(s).Values()
+}
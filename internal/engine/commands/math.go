@@ -0,0 +1,331 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// The default number of digits displayed by format.
+const digits = 6
+
+// MathFunctions returns a mapping of names to math methods.
+//
+// Numbers in oh are exact rationals. Approximate functions, such as exp,
+// log and the trigonometric functions, return inexact numbers calculated
+// using floating point. Functions that can calculate a result exactly do
+// so unless one of their arguments is inexact.
+func MathFunctions() map[string]func(cell.I) cell.I {
+	return map[string]func(cell.I) cell.I{
+		"abs":         abs,
+		"acos":        float(math.Acos),
+		"asin":        float(math.Asin),
+		"atan":        atan,
+		"bit-and":     bitwise((*big.Int).And),
+		"bit-not":     bitNot,
+		"bit-or":      bitwise((*big.Int).Or),
+		"bit-xor":     bitwise((*big.Int).Xor),
+		"ceil":        rounding(ceil),
+		"cos":         float(math.Cos),
+		"e":           constant(math.E),
+		"exact":       exact,
+		"exact?":      isExact,
+		"exp":         float(math.Exp),
+		"floor":       rounding(floor),
+		"format":      format,
+		"inexact":     makeInexact,
+		"inexact?":    isInexact,
+		"log":         logarithm,
+		"pi":          constant(math.Pi),
+		"pow":         pow,
+		"quotient":    quotient,
+		"round":       rounding(round),
+		"shift-left":  shift((*big.Int).Lsh),
+		"shift-right": shift((*big.Int).Rsh),
+		"sin":         float(math.Sin),
+		"sqrt":        sqrt,
+		"tan":         float(math.Tan),
+		"truncate":    rounding(truncate),
+	}
+}
+
+func abs(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return result(new(big.Rat).Abs(rational.Number(v[0])), args)
+}
+
+func atan(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 2)
+
+	if len(v) == 1 {
+		return num.Float(math.Atan(float64Value(v[0])))
+	}
+
+	return num.Float(math.Atan2(float64Value(v[0]), float64Value(v[1])))
+}
+
+func bitNot(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return result(new(big.Rat).SetInt(new(big.Int).Not(integral(v[0]))), args)
+}
+
+func bitwise(op func(z, x, y *big.Int) *big.Int) func(cell.I) cell.I {
+	return func(args cell.I) cell.I {
+		v := validate.Fixed(args, 2, 2)
+
+		i := op(new(big.Int), integral(v[0]), integral(v[1]))
+
+		return result(new(big.Rat).SetInt(i), args)
+	}
+}
+
+func ceil(r *big.Rat) *big.Int {
+	i := floor(r)
+	if !r.IsInt() {
+		i.Add(i, big.NewInt(1))
+	}
+
+	return i
+}
+
+func constant(f float64) func(cell.I) cell.I {
+	return func(args cell.I) cell.I {
+		validate.Fixed(args, 0, 0)
+
+		return num.Float(f)
+	}
+}
+
+func exact(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Rat(rational.Number(v[0]))
+}
+
+func float(f func(float64) float64) func(cell.I) cell.I {
+	return func(args cell.I) cell.I {
+		v := validate.Fixed(args, 1, 1)
+
+		return num.Float(f(float64Value(v[0])))
+	}
+}
+
+func float64Value(c cell.I) float64 {
+	f, _ := rational.Number(c).Float64()
+
+	return f
+}
+
+func floor(r *big.Rat) *big.Int {
+	// The denominator is always positive so Euclidean division is floor.
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// Format returns the text of a number as a fraction, as a decimal with a
+// fixed number of digits after the decimal point, or in scientific
+// notation with a fixed number of digits after the decimal point.
+func format(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 3)
+
+	if len(v) == 1 {
+		return str.New(common.String(v[0]))
+	}
+
+	r := rational.Number(v[0])
+
+	n := digits
+	if len(v) == 3 { //nolint:gomnd
+		n = int(integer.Value(v[2]))
+		if n < 0 {
+			panic(exception.New(exception.Error, "number of digits must not be negative"))
+		}
+	}
+
+	switch style := common.String(v[1]); style {
+	case "fixed":
+		return str.New(r.FloatString(n))
+	case "fraction":
+		return str.New(r.RatString())
+	case "scientific":
+		const precision = 256
+
+		return str.New(new(big.Float).SetPrec(precision).SetRat(r).Text('e', n))
+	default:
+		panic(exception.New(exception.Error, "'"+style+"' is not a number format"))
+	}
+}
+
+// Integral returns the integer value of a number, if it has one.
+func integral(c cell.I) *big.Int {
+	r := rational.Number(c)
+	if !r.IsInt() {
+		panic(exception.New(exception.TypeError, common.String(c)+" is not an integer"))
+	}
+
+	return r.Num()
+}
+
+func isExact(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	rational.Number(v[0])
+
+	return create.Bool(!inexact(list.New(v[0])))
+}
+
+func isInexact(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	rational.Number(v[0])
+
+	return create.Bool(inexact(list.New(v[0])))
+}
+
+// Logarithm returns the natural logarithm of a number or, if a base is
+// specified, the logarithm in that base.
+func logarithm(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 2)
+
+	l := math.Log(float64Value(v[0]))
+	if len(v) == 2 { //nolint:gomnd
+		l /= math.Log(float64Value(v[1]))
+	}
+
+	return num.Float(l)
+}
+
+func makeInexact(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Float(float64Value(v[0]))
+}
+
+// Pow is exact when the exponent is an integer. Otherwise it is inexact.
+func pow(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	x := rational.Number(v[0])
+	y := rational.Number(v[1])
+
+	if !y.IsInt() {
+		return num.Float(math.Pow(float64Value(v[0]), float64Value(v[1])))
+	}
+
+	e := new(big.Int).Abs(y.Num())
+
+	r := new(big.Rat).SetFrac(
+		new(big.Int).Exp(x.Num(), e, nil),
+		new(big.Int).Exp(x.Denom(), e, nil),
+	)
+
+	if y.Sign() < 0 {
+		if r.Sign() == 0 {
+			panic(exception.New(exception.Arithmetic, "division by zero"))
+		}
+
+		r.Inv(r)
+	}
+
+	return result(r, args)
+}
+
+// Quotient performs Euclidean division on integers. The results of
+// quotient and mod satisfy a = b * (quotient a b) + (mod a b).
+func quotient(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	d := integral(v[1])
+	if d.Sign() == 0 {
+		panic(exception.New(exception.Arithmetic, "division by zero"))
+	}
+
+	return result(new(big.Rat).SetInt(new(big.Int).Div(integral(v[0]), d)), args)
+}
+
+// Round rounds half away from zero.
+func round(r *big.Rat) *big.Int {
+	half := new(big.Rat).Abs(r)
+	half.Add(half, big.NewRat(1, 2)) //nolint:gomnd
+
+	i := floor(half)
+	if r.Sign() < 0 {
+		i.Neg(i)
+	}
+
+	return i
+}
+
+// Rounding returns a method that rounds a number to an integer or, if a
+// number of decimal places is specified, to that many decimal places.
+func rounding(f func(*big.Rat) *big.Int) func(cell.I) cell.I {
+	return func(args cell.I) cell.I {
+		v := validate.Fixed(args, 1, 2)
+
+		r := rational.Number(v[0])
+
+		scale := big.NewRat(1, 1)
+		if len(v) == 2 { //nolint:gomnd
+			places := integer.Value(v[1])
+			if places < 0 {
+				panic(exception.New(exception.Error, "number of places must not be negative"))
+			}
+
+			scale.SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(places), nil)) //nolint:gomnd
+		}
+
+		scaled := new(big.Rat).SetInt(f(new(big.Rat).Mul(r, scale)))
+
+		return result(scaled.Quo(scaled, scale), list.New(v[0]))
+	}
+}
+
+func shift(op func(z, x *big.Int, n uint) *big.Int) func(cell.I) cell.I {
+	return func(args cell.I) cell.I {
+		v := validate.Fixed(args, 2, 2)
+
+		n := integer.Value(v[1])
+		if n < 0 {
+			panic(exception.New(exception.Error, "shift count must not be negative"))
+		}
+
+		return result(new(big.Rat).SetInt(op(new(big.Int), integral(v[0]), uint(n))), args)
+	}
+}
+
+// Sqrt is exact when the argument is the square of a rational number.
+func sqrt(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	r := rational.Number(v[0])
+	if r.Sign() < 0 {
+		panic(exception.New(exception.Arithmetic, "square root of a negative number"))
+	}
+
+	n := new(big.Int).Sqrt(r.Num())
+	d := new(big.Int).Sqrt(r.Denom())
+
+	exact := new(big.Rat).SetFrac(n, d)
+	if new(big.Rat).Mul(exact, exact).Cmp(r) == 0 {
+		return result(exact, args)
+	}
+
+	return num.Float(math.Sqrt(float64Value(v[0])))
+}
+
+func truncate(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
@@ -29,6 +29,10 @@ func isNumber(args cell.I) cell.I {
 func number(args cell.I) cell.I {
 	v := validate.Fixed(args, 1, 1)
 
+	if num.Is(v[0]) {
+		return v[0]
+	}
+
 	if r, ok := v[0].(rational.I); ok {
 		return num.Rat(r.Rat())
 	}
@@ -4,11 +4,23 @@ package commands
 
 import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
 	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/pipe"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
 )
 
+// PipeMethods returns a mapping of names to methods for pipes. Pipes also
+// have all conduit methods.
+func PipeMethods() map[string]func(cell.I, cell.I) cell.I {
+	return map[string]func(cell.I, cell.I) cell.I{
+		"read-all":    readAll,
+		"read-bytes":  readBytes,
+		"write-bytes": writeBytes,
+	}
+}
+
 func isPipe(args cell.I) cell.I {
 	v := validate.Fixed(args, 1, 1)
 
@@ -20,3 +32,25 @@ func makePipe(args cell.I) cell.I {
 
 	return pipe.New(nil, nil)
 }
+
+func readAll(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return pipe.To(s).ReadAll()
+}
+
+func readBytes(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return pipe.To(s).ReadBytes(int(integer.Value(v[0])))
+}
+
+func writeBytes(s, args cell.I) cell.I {
+	p := pipe.To(s)
+
+	for l := args; l != pair.Null; l = pair.Cdr(l) {
+		p.WriteBytes(pair.Car(l))
+	}
+
+	return args
+}
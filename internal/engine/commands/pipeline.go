@@ -0,0 +1,80 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"strconv"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/pipeline"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// PipelineMethods returns a mapping of names to methods for pipeline results.
+func PipelineMethods() map[string]func(cell.I, cell.I) cell.I {
+	return map[string]func(cell.I, cell.I) cell.I{
+		"length":   pipelineLength,
+		"status":   pipelineStatus,
+		"statuses": pipelineStatuses,
+	}
+}
+
+func isPipeline(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(pipeline.Is(v[0]))
+}
+
+// The arguments to makePipeline are a pipefail flag followed by a
+// (index . result) pair for each stage. The pairs may be in any order.
+func makePipeline(args cell.I) cell.I {
+	v, args := validate.Variadic(args, 2, 2)
+
+	fail := boolean.Value(v[0])
+
+	stages := make([]cell.I, list.Length(args)+1)
+
+	for c := pair.Cons(v[1], args); c != pair.Null; c = pair.Cdr(c) {
+		p := pair.To(pair.Car(c))
+
+		i := integer.Value(pair.Car(p))
+		if i < 0 || i >= int64(len(stages)) || stages[i] != nil {
+			panic(exception.New(exception.Error, "invalid pipeline stage "+strconv.FormatInt(i, 10)))
+		}
+
+		stages[i] = pair.Cdr(p)
+	}
+
+	return pipeline.New(fail, stages...)
+}
+
+func pipelineLength(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return num.Int(pipeline.To(s).Length())
+}
+
+func pipelineStatus(s, args cell.I) cell.I {
+	v := validate.Fixed(args, 0, 1)
+
+	p := pipeline.To(s)
+
+	if len(v) == 0 {
+		return p.Result()
+	}
+
+	return p.Stage(int(integer.Value(v[0])))
+}
+
+func pipelineStatuses(s, args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return pipeline.To(s).Stages()
+}
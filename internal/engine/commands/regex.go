@@ -0,0 +1,151 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"regexp"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/regex"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// RegexFunctions returns a mapping of names to regular expression methods.
+// Each method, other than compile and quote, takes a pattern as its first
+// argument. The pattern may be a compiled regex or a string.
+func RegexFunctions() map[string]func(cell.I) cell.I {
+	return map[string]func(cell.I) cell.I{
+		"compile":    compileRegex,
+		"find":       find,
+		"find-all":   findAll,
+		"match?":     isRegexMatch,
+		"quote":      quoteRegex,
+		"split":      split,
+		"submatches": submatches,
+	}
+}
+
+// Submatches converts the pairs of indices in loc, as returned by
+// FindStringSubmatchIndex, to a list of strings. Groups that did not
+// participate in the match are ().
+func Submatches(s string, loc []int) cell.I {
+	l := make([]cell.I, len(loc)/2) //nolint:gomnd
+
+	for i := range l {
+		l[i] = submatch(s, loc, i)
+	}
+
+	return list.New(l...)
+}
+
+func compileRegex(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return regex.New(common.String(v[0]))
+}
+
+func find(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	s := common.String(v[1])
+
+	loc := regex.Compile(v[0]).FindStringIndex(s)
+	if loc == nil {
+		return pair.Null
+	}
+
+	return str.New(s[loc[0]:loc[1]])
+}
+
+func findAll(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 3)
+
+	return stringList(regex.Compile(v[0]).FindAllString(common.String(v[1]), limit(v, 2)))
+}
+
+func isRegex(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(regex.Is(v[0]))
+}
+
+func isRegexMatch(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	return create.Bool(regex.Compile(v[0]).MatchString(common.String(v[1])))
+}
+
+func limit(v []cell.I, i int) int {
+	if len(v) > i {
+		return int(integer.Value(v[i]))
+	}
+
+	return -1
+}
+
+func quoteRegex(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return str.New(regexp.QuoteMeta(common.String(v[0])))
+}
+
+func split(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 3)
+
+	return stringList(regex.Compile(v[0]).Split(common.String(v[1]), limit(v, 2)))
+}
+
+func stringList(s []string) cell.I {
+	l := make([]cell.I, len(s))
+	for i, e := range s {
+		l[i] = str.New(e)
+	}
+
+	return list.New(l...)
+}
+
+func submatch(s string, loc []int, i int) cell.I {
+	start, end := loc[2*i], loc[2*i+1]
+	if start < 0 {
+		return pair.Null
+	}
+
+	return str.New(s[start:end])
+}
+
+// If the pattern has named groups, submatches returns a map of names to
+// matched text. Otherwise it returns a list containing the text of the
+// entire match followed by the text of each group.
+func submatches(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	r := regex.Compile(v[0])
+	s := common.String(v[1])
+
+	loc := r.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return pair.Null
+	}
+
+	kvs := []cell.I{}
+
+	for i, n := range r.SubexpNames() {
+		if n != "" {
+			kvs = append(kvs, sym.New(n), submatch(s, loc, i))
+		}
+	}
+
+	if len(kvs) == 0 {
+		return Submatches(s, loc)
+	}
+
+	return hmap.New(kvs...)
+}
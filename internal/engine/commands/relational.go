@@ -3,62 +3,66 @@
 package commands
 
 import (
+	"math/big"
+
+	"github.com/michaelmacinnis/oh/internal/common"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/duration"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/type/timestamp"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
 )
 
-func equal(args cell.I) cell.I {
-	v, rest := validate.Variadic(args, 2, 2)
-
-	for {
-		if !v[0].Equal(v[1]) {
-			return pair.Null
-		}
-
-		if rest == pair.Null {
-			return sym.True
-		}
-
-		v[0] = v[1]
-		v[1] = pair.Car(rest)
-
-		rest = pair.Cdr(rest)
+// Less is the default ordering for sorting. Numbers are ordered numerically
+// and come before everything else, which is ordered by its string value.
+func Less(a, b cell.I) bool {
+	x, xok := numeric(a)
+	y, yok := numeric(b)
+
+	switch {
+	case xok && yok:
+		return x.Cmp(y) < 0
+	case xok != yok:
+		return xok
 	}
-}
-
-func ge(args cell.I) cell.I {
-	v, rest := validate.Variadic(args, 2, 2)
 
-	prev := rational.Number(v[0])
-	curr := rational.Number(v[1])
+	if duration.Is(a) && duration.Is(b) || timestamp.Is(a) && timestamp.Is(b) {
+		return compare(a, b) < 0
+	}
 
-	for {
-		if prev.Cmp(curr) < 0 {
-			return pair.Null
-		}
+	return common.String(a) < common.String(b)
+}
 
-		if rest == pair.Null {
-			return sym.True
+// Compare returns -1, 0, or +1 if a is less than, equal to, or greater than
+// b. If either is a duration both are compared as durations. Timestamps are
+// compared with timestamps and anything else is compared as a number.
+func compare(a, b cell.I) int {
+	switch {
+	case duration.Is(a) || duration.Is(b):
+		x, y := Duration(a), Duration(b)
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
 		}
 
-		prev = curr
-		curr = rational.Number(pair.Car(rest))
-
-		rest = pair.Cdr(rest)
+		return 0
+	case timestamp.Is(a):
+		return timestamp.To(a).Compare(timestamp.To(b))
 	}
+
+	return rational.Number(a).Cmp(rational.Number(b))
 }
 
-func gt(args cell.I) cell.I {
+func equal(args cell.I) cell.I {
 	v, rest := validate.Variadic(args, 2, 2)
 
-	prev := rational.Number(v[0])
-	curr := rational.Number(v[1])
-
 	for {
-		if prev.Cmp(curr) <= 0 {
+		if !v[0].Equal(v[1]) {
 			return pair.Null
 		}
 
@@ -66,43 +70,53 @@ func gt(args cell.I) cell.I {
 			return sym.True
 		}
 
-		prev = curr
-		curr = rational.Number(pair.Car(rest))
+		v[0] = v[1]
+		v[1] = pair.Car(rest)
 
 		rest = pair.Cdr(rest)
 	}
 }
 
-func le(args cell.I) cell.I {
-	v, rest := validate.Variadic(args, 2, 2)
+func ge(args cell.I) cell.I {
+	return ordered(args, func(c int) bool { return c >= 0 })
+}
 
-	prev := rational.Number(v[0])
-	curr := rational.Number(v[1])
+func gt(args cell.I) cell.I {
+	return ordered(args, func(c int) bool { return c > 0 })
+}
 
-	for {
-		if prev.Cmp(curr) > 0 {
-			return pair.Null
-		}
+func le(args cell.I) cell.I {
+	return ordered(args, func(c int) bool { return c <= 0 })
+}
 
-		if rest == pair.Null {
-			return sym.True
-		}
+func lt(args cell.I) cell.I {
+	return ordered(args, func(c int) bool { return c < 0 })
+}
 
-		prev = curr
-		curr = rational.Number(pair.Car(rest))
+func numeric(c cell.I) (*big.Rat, bool) {
+	// Not every symbol is a number.
+	if sym.Is(c) {
+		return (&big.Rat{}).SetString(common.String(c))
+	}
 
-		rest = pair.Cdr(rest)
+	n, ok := c.(rational.I)
+	if !ok {
+		return nil, false
 	}
+
+	return n.Rat(), true
 }
 
-func lt(args cell.I) cell.I {
+// Ordered returns true if each argument and the argument following it
+// satisfy the relation ok; Otherwise it returns false.
+func ordered(args cell.I, ok func(int) bool) cell.I {
 	v, rest := validate.Variadic(args, 2, 2)
 
-	prev := rational.Number(v[0])
-	curr := rational.Number(v[1])
+	prev := v[0]
+	curr := v[1]
 
 	for {
-		if prev.Cmp(curr) >= 0 {
+		if !ok(compare(prev, curr)) {
 			return pair.Null
 		}
 
@@ -111,7 +125,7 @@ func lt(args cell.I) cell.I {
 		}
 
 		prev = curr
-		curr = rational.Number(pair.Car(rest))
+		curr = pair.Car(rest)
 
 		rest = pair.Cdr(rest)
 	}
@@ -0,0 +1,120 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/michaelmacinnis/oh/internal/system/process"
+)
+
+// sandboxConfig creates a sandbox from options of the form:
+//
+//	--ipc --mount --net --pid --user --uts
+//	--chroot DIR
+//	--uid-map INSIDE OUTSIDE SIZE
+//	--gid-map INSIDE OUTSIDE SIZE
+//	--cgroup PATH --cpu MAX --memory MAX
+//
+// Namespace options can be repeated. So can the ID map options.
+func sandboxConfig(args cell.I) cell.I {
+	s := &process.Sandbox{}
+
+	for args != pair.Null {
+		option := common.String(pair.Car(args))
+		args = pair.Cdr(args)
+
+		var v []cell.I
+
+		switch strings.TrimLeft(option, "-") {
+		case "ipc":
+			s.IPC = true
+		case "mount":
+			s.Mount = true
+		case "net":
+			s.Net = true
+		case "pid":
+			s.PID = true
+		case "user":
+			s.User = true
+		case "uts":
+			s.UTS = true
+
+		case "chroot":
+			v, args = validate.Variadic(args, 1, 1)
+			s.Chroot = common.String(v[0])
+
+		case "gid-map":
+			v, args = validate.Variadic(args, 3, 3) //nolint:gomnd
+			s.GIDMap = append(s.GIDMap, idMap(v))
+
+		case "uid-map":
+			v, args = validate.Variadic(args, 3, 3) //nolint:gomnd
+			s.UIDMap = append(s.UIDMap, idMap(v))
+
+		case "cgroup":
+			v, args = validate.Variadic(args, 1, 1)
+			s.Cgroup = common.String(v[0])
+
+		case "cpu":
+			v, args = validate.Variadic(args, 1, 1)
+			s.CPU = common.String(v[0])
+
+		case "memory":
+			v, args = validate.Variadic(args, 1, 1)
+			s.Memory = common.String(v[0])
+
+		default:
+			panic(exception.New(exception.Error, "unknown sandbox option: "+option))
+		}
+	}
+
+	if (s.CPU != "" || s.Memory != "") && s.Cgroup == "" {
+		panic(exception.New(exception.Error, "cpu and memory limits require a cgroup"))
+	}
+
+	if (len(s.UIDMap) > 0 || len(s.GIDMap) > 0) && !s.User {
+		s.User = true
+	}
+
+	err := s.Prepare()
+	if err != nil {
+		panic(exception.Wrap(exception.IO, err))
+	}
+
+	return s
+}
+
+// sandboxRelease removes the sandbox's control group, if it was created
+// for the sandbox and is no longer in use.
+func sandboxRelease(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	s, ok := v[0].(*process.Sandbox)
+	if !ok {
+		panic(exception.New(exception.TypeError, "expected a sandbox, not a "+v[0].Name()))
+	}
+
+	s.Release()
+
+	return pair.Null
+}
+
+func idMap(v []cell.I) process.IDMap {
+	id := func(c cell.I) int {
+		return int(integer.Value(num.New(common.String(c))))
+	}
+
+	return process.IDMap{
+		Inside:  id(v[0]),
+		Outside: id(v[1]),
+		Size:    id(v[2]),
+	}
+}
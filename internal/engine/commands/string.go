@@ -5,31 +5,175 @@ package commands
 import (
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/michaelmacinnis/oh/internal/common"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
 	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/rivo/uniseg"
 )
 
 // StringFunctions returns a mapping of names to string methods.
+//
+// Lengths, indices and slices count characters, as a person would. That is,
+// grapheme clusters rather than bytes or code points. The byte- variants
+// count bytes. Widths and padding are in terminal columns.
 func StringFunctions() map[string]func(cell.I) cell.I {
 	return map[string]func(cell.I) cell.I{
-		"format":      sprintf,
-		"length":      slength,
-		"lower":       lower,
-		"replace":     sreplace,
-		"slice":       sslice,
-		"trim-prefix": trimPrefix,
-		"trim-suffix": trimSuffix,
-		"upper":       upper,
+		"byte-index":   byteIndex,
+		"byte-length":  byteLength,
+		"byte-slice":   byteSlice,
+		"contains?":    contains,
+		"ends-with?":   endsWith,
+		"fields":       fields,
+		"format":       sprintf,
+		"index":        sindex,
+		"length":       slength,
+		"lower":        lower,
+		"pad-left":     padLeft,
+		"pad-right":    padRight,
+		"repeat":       repeat,
+		"replace":      sreplace,
+		"slice":        sslice,
+		"starts-with?": startsWith,
+		"title":        title,
+		"trim":         trim,
+		"trim-left":    trimLeft,
+		"trim-prefix":  trimPrefix,
+		"trim-right":   trimRight,
+		"trim-suffix":  trimSuffix,
+		"upper":        upper,
+		"width":        width,
 	}
 }
 
+// bounds returns the start and end of a slice of an item with the
+// specified length. A negative end is relative to the end of the item.
+func bounds(v []cell.I, length int64) (int64, int64) {
+	start := integer.Value(v[1])
+	if start < 0 {
+		panic(exception.New(exception.Error, "slice starts before first element"))
+	} else if start > length {
+		start = length
+	}
+
+	end := length
+	if len(v) == 3 { //nolint:gomnd
+		end = integer.Value(v[2])
+		if end > length {
+			end = length
+		} else if end < 0 {
+			end = length + end
+		}
+	}
+
+	if end < start {
+		panic(exception.New(exception.Error, "end of slice before start"))
+	}
+
+	return start, end
+}
+
+func byteIndex(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	i := strings.Index(common.String(v[0]), common.String(v[1]))
+	if i < 0 {
+		return pair.Null
+	}
+
+	return num.Int(i)
+}
+
+func byteLength(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Int(len(common.String(v[0])))
+}
+
+func byteSlice(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 3)
+
+	s := common.String(v[0])
+
+	start, end := bounds(v, int64(len(s)))
+
+	return str.New(s[start:end])
+}
+
+// clusterIndex returns the index of the first occurrence of the grapheme
+// clusters sub in g, or -1 if there is none.
+func clusterIndex(g, sub []string) int {
+	for i := 0; i+len(sub) <= len(g); i++ {
+		if clustersAt(g, sub, i) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// clustersAt returns true if the grapheme clusters sub occur in g at index i.
+func clustersAt(g, sub []string, i int) bool {
+	if i < 0 || i+len(sub) > len(g) {
+		return false
+	}
+
+	for j := range sub {
+		if g[i+j] != sub[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	return create.Bool(clusterIndex(g, sub) >= 0)
+}
+
+func endsWith(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	return create.Bool(clustersAt(g, sub, len(g)-len(sub)))
+}
+
+func fields(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return stringList(strings.Fields(common.String(v[0])))
+}
+
+// graphemes splits s into grapheme clusters.
+func graphemes(s string) []string {
+	l := []string{}
+
+	state := -1
+	for s != "" {
+		var c string
+
+		c, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+
+		l = append(l, c)
+	}
+
+	return l
+}
+
 func isString(args cell.I) cell.I {
 	v := validate.Fixed(args, 1, 1)
 
@@ -48,41 +192,88 @@ func makeString(args cell.I) cell.I {
 	return str.New(common.String(v[0]))
 }
 
-func slength(args cell.I) cell.I {
-	v := validate.Fixed(args, 1, 1)
-
-	return num.Int(len(common.String(v[0])))
-}
-
-func sslice(args cell.I) cell.I {
+// pad returns the string to be padded and the padding required for it to
+// occupy the requested number of terminal columns. By default, the string
+// is padded with spaces. The padding is never wider than requested.
+func pad(args cell.I) (string, string) {
 	v := validate.Fixed(args, 2, 3)
 
 	s := common.String(v[0])
+	n := int(integer.Value(v[1])) - uniseg.StringWidth(s)
 
-	length := int64(len(s))
+	p := " "
+	if len(v) == 3 { //nolint:gomnd
+		p = common.String(v[2])
+	}
 
-	start := integer.Value(v[1])
-	if start < 0 {
-		panic("slice starts before first element")
-	} else if start > length {
-		start = length
+	w := uniseg.StringWidth(p)
+	if w == 0 {
+		panic(exception.New(exception.Error, "padding must not be empty"))
 	}
 
-	end := length
-	if len(v) == 3 { //nolint:gomnd
-		end = integer.Value(v[2])
-		if end > length {
-			end = length
-		} else if end < 0 {
-			end = length + end
-		}
+	var b strings.Builder
+
+	for ; n >= w; n -= w {
+		b.WriteString(p)
 	}
 
-	if end < start {
-		panic("end of slice before start")
+	return s, b.String()
+}
+
+func padLeft(args cell.I) cell.I {
+	s, padding := pad(args)
+
+	return str.New(padding + s)
+}
+
+func padRight(args cell.I) cell.I {
+	s, padding := pad(args)
+
+	return str.New(s + padding)
+}
+
+func repeat(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	n := integer.Value(v[1])
+	if n < 0 {
+		panic(exception.New(exception.Error, "repeat count must not be negative"))
 	}
 
-	return str.New(s[start:end])
+	return str.New(strings.Repeat(common.String(v[0]), int(n)))
+}
+
+// sindex returns the index of the first occurrence of a substring. Only
+// whole characters match so "e" is not found in "é" when it is written as
+// an "e" followed by a combining accent.
+func sindex(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	i := clusterIndex(g, sub)
+	if i < 0 {
+		return pair.Null
+	}
+
+	return num.Int(i)
+}
+
+func slength(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Int(uniseg.GraphemeClusterCount(common.String(v[0])))
+}
+
+func sslice(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 3)
+
+	g := graphemes(common.String(v[0]))
+
+	start, end := bounds(v, int64(len(g)))
+
+	return str.New(strings.Join(g[start:end], ""))
 }
 
 func sreplace(args cell.I) cell.I {
@@ -101,7 +292,10 @@ func sreplace(args cell.I) cell.I {
 	return str.New(strings.Replace(s, old, replacement, n))
 }
 
-// TODO: Extend oh types to play nicer with fmt and Sprintf.
+// sprintf formats its arguments according to a format string. All cells
+// implement fmt.Formatter so, in addition to the usual verbs, %l produces
+// the literal form of a value, %Q produces its text quoted for a shell, and
+// %j produces its JSON form.
 func sprintf(args cell.I) cell.I {
 	v, args := validate.Variadic(args, 1, 1)
 
@@ -115,16 +309,74 @@ func sprintf(args cell.I) cell.I {
 	return str.New(fmt.Sprintf(common.String(v[0]), argv...))
 }
 
+func startsWith(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	return create.Bool(clustersAt(g, sub, 0))
+}
+
+// title converts the first letter of each word to title case.
+func title(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	previous := ' '
+
+	return str.New(strings.Map(func(r rune) rune {
+		defer func() { previous = r }()
+
+		if unicode.IsLetter(previous) || unicode.IsDigit(previous) || previous == '\'' {
+			return r
+		}
+
+		return unicode.ToTitle(r)
+	}, common.String(v[0])))
+}
+
+// trim, trimLeft and trimRight remove leading and/or trailing white space
+// or, if a cutset is specified, any of the characters in the cutset.
+func trim(args cell.I) cell.I {
+	return trimmer(args, strings.TrimSpace, strings.Trim)
+}
+
+func trimLeft(args cell.I) cell.I {
+	return trimmer(args, func(s string) string {
+		return strings.TrimLeftFunc(s, unicode.IsSpace)
+	}, strings.TrimLeft)
+}
+
 func trimPrefix(args cell.I) cell.I {
 	v := validate.Fixed(args, 2, 2)
 
-	return str.New(strings.TrimPrefix(common.String(v[0]), common.String(v[1])))
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	if clustersAt(g, sub, 0) {
+		g = g[len(sub):]
+	}
+
+	return str.New(strings.Join(g, ""))
+}
+
+func trimRight(args cell.I) cell.I {
+	return trimmer(args, func(s string) string {
+		return strings.TrimRightFunc(s, unicode.IsSpace)
+	}, strings.TrimRight)
 }
 
 func trimSuffix(args cell.I) cell.I {
 	v := validate.Fixed(args, 2, 2)
 
-	return str.New(strings.TrimSuffix(common.String(v[0]), common.String(v[1])))
+	g := graphemes(common.String(v[0]))
+	sub := graphemes(common.String(v[1]))
+
+	if clustersAt(g, sub, len(g)-len(sub)) {
+		g = g[:len(g)-len(sub)]
+	}
+
+	return str.New(strings.Join(g, ""))
 }
 
 func upper(args cell.I) cell.I {
@@ -132,3 +384,20 @@ func upper(args cell.I) cell.I {
 
 	return str.New(strings.ToUpper(common.String(v[0])))
 }
+
+func trimmer(args cell.I, space func(string) string, cutset func(string, string) string) cell.I {
+	v := validate.Fixed(args, 1, 2)
+
+	s := common.String(v[0])
+	if len(v) == 1 {
+		return str.New(space(s))
+	}
+
+	return str.New(cutset(s, common.String(v[1])))
+}
+
+func width(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Int(uniseg.StringWidth(common.String(v[0])))
+}
@@ -0,0 +1,364 @@
+// Released under an MIT license. See LICENSE.
+
+package commands
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/duration"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/type/timestamp"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+const nanoseconds = 1e9
+
+// Named layouts. Any other layout containing a % is a strftime-style
+// layout. Anything else is a Go-style layout.
+//
+//nolint:gochecknoglobals
+var layouts = map[string]string{
+	"ansic":       time.ANSIC,
+	"date-only":   time.DateOnly,
+	"date-time":   time.DateTime,
+	"kitchen":     time.Kitchen,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"time-only":   time.TimeOnly,
+	"unix-date":   time.UnixDate,
+}
+
+// The Go-style equivalents of supported strftime directives.
+//
+//nolint:gochecknoglobals
+var directives = map[byte]string{
+	'%': "%",
+	'A': "Monday",
+	'B': "January",
+	'D': "01/02/06",
+	'F': "2006-01-02",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'R': "15:04",
+	'S': "05",
+	'T': "15:04:05",
+	'Y': "2006",
+	'Z': "MST",
+	'a': "Mon",
+	'b': "Jan",
+	'd': "02",
+	'e': "_2",
+	'h': "Jan",
+	'j': "002",
+	'm': "01",
+	'n': "\n",
+	'p': "PM",
+	't': "\t",
+	'y': "06",
+	'z': "-0700",
+}
+
+// TimeFunctions returns a mapping of names to time methods.
+//
+// Numbers passed where a duration is expected are a count of seconds and
+// strings are parsed as durations like "1h30m" or "250ms".
+func TimeFunctions() map[string]func(cell.I) cell.I {
+	return map[string]func(cell.I) cell.I{
+		"duration":  makeDuration,
+		"fields":    timeFields,
+		"format":    formatTime,
+		"from-unix": fromUnix,
+		"in":        inZone,
+		"now":       now,
+		"parse":     parseTime,
+		"seconds":   seconds,
+		"since":     since,
+		"unix":      unix,
+		"until":     until,
+		"zone":      zone,
+	}
+}
+
+// Duration returns the value of c, which may be a duration, a number of
+// seconds, or the text of a duration, as a time.Duration.
+func Duration(c cell.I) time.Duration {
+	if duration.Is(c) {
+		return duration.To(c).Duration()
+	}
+
+	if r, ok := numeric(c); ok {
+		ns := new(big.Rat).Mul(r, big.NewRat(nanoseconds, 1))
+
+		i := new(big.Int).Quo(ns.Num(), ns.Denom())
+		if !i.IsInt64() {
+			panic(exception.New(exception.Arithmetic, common.String(c)+" seconds is out of range for a duration"))
+		}
+
+		return time.Duration(i.Int64())
+	}
+
+	return duration.To(duration.New(common.String(c))).Duration()
+}
+
+// Adding to a timestamp or duration adds durations.
+func addTime(args cell.I) cell.I {
+	v, rest := validate.Variadic(args, 1, 1)
+
+	var d time.Duration
+
+	for ; rest != pair.Null; rest = pair.Cdr(rest) {
+		d += Duration(pair.Car(rest))
+	}
+
+	if timestamp.Is(v[0]) {
+		return timestamp.At(timestamp.To(v[0]).Time().Add(d))
+	}
+
+	return duration.Of(duration.To(v[0]).Duration() + d)
+}
+
+func formatTime(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 2)
+
+	l := time.RFC3339
+	if len(v) == 2 { //nolint:gomnd
+		l = layout(v[1])
+	}
+
+	return str.New(timestamp.To(v[0]).Time().Format(l))
+}
+
+// FromUnix returns the timestamp for a number of seconds since the epoch.
+func fromUnix(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	r := new(big.Rat).Mul(rational.Number(v[0]), big.NewRat(nanoseconds, 1))
+
+	ns := new(big.Int).Quo(r.Num(), r.Denom())
+	s, n := new(big.Int).DivMod(ns, big.NewInt(nanoseconds), new(big.Int))
+
+	if !s.IsInt64() {
+		panic(exception.New(exception.Arithmetic, common.String(v[0])+" seconds is out of range for a timestamp"))
+	}
+
+	return timestamp.At(time.Unix(s.Int64(), n.Int64()))
+}
+
+func inZone(args cell.I) cell.I {
+	v := validate.Fixed(args, 2, 2)
+
+	return timestamp.At(timestamp.To(v[0]).Time().In(location(v[1])))
+}
+
+func isDuration(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(duration.Is(v[0]))
+}
+
+func isTimestamp(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return create.Bool(timestamp.Is(v[0]))
+}
+
+// Layout returns the Go-style layout for a named, strftime-style or
+// Go-style layout. Text in a strftime-style layout, other than directives,
+// is passed through as is and so should not contain Go-style elements.
+func layout(c cell.I) string {
+	s := common.String(c)
+
+	if l, ok := layouts[s]; ok {
+		return l
+	}
+
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		i++
+		if i == len(s) {
+			panic(exception.New(exception.Syntax, "layout '"+s+"' ends with an incomplete directive"))
+		}
+
+		d, ok := directives[s[i]]
+		if !ok {
+			panic(exception.New(exception.Syntax, "'%"+string(s[i])+"' is not a supported directive"))
+		}
+
+		b.WriteString(d)
+	}
+
+	return b.String()
+}
+
+func location(c cell.I) *time.Location {
+	name := common.String(c)
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(exception.New(exception.Error, "'"+name+"' is not a known time zone"))
+	}
+
+	return loc
+}
+
+func makeDuration(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return duration.Of(Duration(v[0]))
+}
+
+func now(args cell.I) cell.I {
+	validate.Fixed(args, 0, 0)
+
+	return timestamp.At(time.Now())
+}
+
+// ParseTime parses text using a layout, RFC 3339 by default. Times without
+// a time zone are in UTC unless a time zone is specified.
+func parseTime(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 3)
+
+	s := common.String(v[0])
+
+	name, l := "rfc3339", time.RFC3339
+	if len(v) > 1 {
+		name, l = common.String(v[1]), layout(v[1])
+	}
+
+	loc := time.UTC
+	if len(v) == 3 { //nolint:gomnd
+		loc = location(v[2])
+	}
+
+	t, err := time.ParseInLocation(l, s, loc)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "'"+s+"' does not match the layout '"+name+"'"))
+	}
+
+	return timestamp.At(t)
+}
+
+// Seconds returns the length of a duration as a number of seconds.
+func seconds(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return num.Rat(big.NewRat(int64(Duration(v[0])), nanoseconds))
+}
+
+func since(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return duration.Of(time.Since(timestamp.To(v[0]).Time()))
+}
+
+// Subtracting from a timestamp or duration subtracts durations except
+// that the difference between two timestamps is a duration.
+func subTime(args cell.I) cell.I {
+	v, rest := validate.Variadic(args, 1, 1)
+
+	if timestamp.Is(v[0]) && rest != pair.Null && timestamp.Is(pair.Car(rest)) {
+		v = validate.Fixed(args, 2, 2)
+
+		return duration.Of(timestamp.To(v[0]).Time().Sub(timestamp.To(v[1]).Time()))
+	}
+
+	var d time.Duration
+
+	for ; rest != pair.Null; rest = pair.Cdr(rest) {
+		d += Duration(pair.Car(rest))
+	}
+
+	if timestamp.Is(v[0]) {
+		return timestamp.At(timestamp.To(v[0]).Time().Add(-d))
+	}
+
+	return duration.Of(duration.To(v[0]).Duration() - d)
+}
+
+// Temporal returns true if the first argument is a timestamp or duration.
+func temporal(args cell.I) bool {
+	if args == pair.Null {
+		return false
+	}
+
+	c := pair.Car(args)
+
+	return timestamp.Is(c) || duration.Is(c)
+}
+
+// TimeFields returns a map of the components of a timestamp.
+func timeFields(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	t := timestamp.To(v[0]).Time()
+
+	name, offset := t.Zone()
+
+	return hmap.New(
+		sym.New("day"), num.Int(t.Day()),
+		sym.New("hour"), num.Int(t.Hour()),
+		sym.New("minute"), num.Int(t.Minute()),
+		sym.New("month"), num.Int(int(t.Month())),
+		sym.New("nanosecond"), num.Int(t.Nanosecond()),
+		sym.New("offset"), num.Int(offset),
+		sym.New("second"), num.Int(t.Second()),
+		sym.New("weekday"), str.New(t.Weekday().String()),
+		sym.New("year"), num.Int(t.Year()),
+		sym.New("yearday"), num.Int(t.YearDay()),
+		sym.New("zone"), str.New(name),
+	)
+}
+
+// Unix returns the number of seconds since the epoch for a timestamp.
+func unix(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	t := timestamp.To(v[0]).Time()
+
+	r := new(big.Rat).SetFrac(big.NewInt(t.Unix()), big.NewInt(1))
+	r.Add(r, big.NewRat(int64(t.Nanosecond()), nanoseconds))
+
+	return num.Rat(r)
+}
+
+func until(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	return duration.Of(time.Until(timestamp.To(v[0]).Time()))
+}
+
+func zone(args cell.I) cell.I {
+	v := validate.Fixed(args, 1, 1)
+
+	name, _ := timestamp.To(v[0]).Time().Zone()
+
+	return str.New(name)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
 	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
 	"github.com/michaelmacinnis/oh/internal/common/type/env"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/obj"
@@ -28,6 +29,7 @@ import (
 	"github.com/michaelmacinnis/oh/internal/engine/task"
 	"github.com/michaelmacinnis/oh/internal/reader"
 	"github.com/michaelmacinnis/oh/internal/system/job"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
 	"github.com/michaelmacinnis/oh/internal/system/process"
 )
 
@@ -43,14 +45,14 @@ func Boot(path string, arguments []string) {
 
 	path, err := filepath.Abs(path)
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	env0.Export("ORIGIN", sym.New(path))
 
 	pwd, err := os.Getwd()
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.IO, err))
 	}
 
 	env0.Export("OLDPWD", sym.New(pwd))
@@ -69,20 +71,24 @@ func Boot(path string, arguments []string) {
 	}
 
 	j := job.Job(0)
-	r := reader.New("boot.oh")
 
-	for _, line := range strings.SplitAfter(boot.Script(), "\n") {
-		c, err := r.Scan(line)
+	// The boot script is parsed at build time. If the parsed form is out of
+	// date, the script is parsed now.
+	cs, err := reader.Decode("boot.oh", boot.Script(), boot.Compiled())
+	if err != nil {
+		cs, err = reader.Commands("boot.oh", boot.Script())
 		if err != nil {
-			panic(err.Error())
+			panic(exception.Wrap(exception.Syntax, err))
 		}
+	}
 
-		if c != nil {
-			System(j, c)
-		}
+	for _, c := range cs {
+		System(j, c)
 	}
 
 	sym.Cache(false)
+
+	policy.Enforce(restrictions)
 }
 
 // Evaluate evaluates the command c.
@@ -103,6 +109,11 @@ func Evaluate(j *job.T, c cell.I) cell.I {
 	return r
 }
 
+// Restrict sets the policy enforced once booted. Boot itself is unrestricted.
+func Restrict(p *policy.T) {
+	restrictions = p
+}
+
 // Resolve returns the string value for a variable.
 func Resolve(k string) (v string) {
 	defer func() {
@@ -141,9 +152,10 @@ func System(j *job.T, c cell.I) (cell.I, bool) {
 
 //nolint:gochecknoglobals
 var (
-	env0   scope.I
-	frame0 *frame.T
-	scope0 scope.I
+	env0         scope.I
+	frame0       *frame.T
+	restrictions *policy.T
+	scope0       scope.I
 )
 
 func bg(t *task.T) task.Op {
@@ -157,7 +169,7 @@ func bg(t *task.T) task.Op {
 	// TODO: Convert this to a function that returns what a wrapper needs.
 	bt := job.Bg(pipe.W(t.CellValue("stdout")), n)
 	if bt == nil {
-		panic("job does not exist")
+		panic(exception.New(exception.Error, "job does not exist"))
 	}
 
 	return t.Return(bt)
@@ -184,7 +196,7 @@ func fg(t *task.T) task.Op {
 
 	// TODO: Convert this to a function that returns what a wrapper needs.
 	if !job.Fg(pipe.W(t.CellValue("stdout")), n) {
-		panic("job does not exist")
+		panic(exception.New(exception.Error, "job does not exist"))
 	}
 
 	return t.Return(sym.True)
@@ -213,7 +225,11 @@ func init() { //nolint:gochecknoinits
 
 	scope0.Define("$", num.Int(process.ID()))
 
+	scope0.Define("bytes", task.BytesScope())
+	scope0.Define("num", task.MathScope())
+	scope0.Define("re", task.RegexScope())
 	scope0.Define("str", task.StringScope())
+	scope0.Define("time", task.TimeScope())
 	scope0.Define("sys", obj.New(env0))
 
 	// Methods.
@@ -5,9 +5,10 @@ package task
 import (
 	"fmt"
 	"os"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/michaelmacinnis/adapted"
 	"github.com/michaelmacinnis/oh/internal/common"
@@ -20,15 +21,21 @@ import (
 	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
 	"github.com/michaelmacinnis/oh/internal/common/type/create"
 	"github.com/michaelmacinnis/oh/internal/common/type/env"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/obj"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/pipe"
+	"github.com/michaelmacinnis/oh/internal/common/type/pipeline"
+	"github.com/michaelmacinnis/oh/internal/common/type/regex"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
 	"github.com/michaelmacinnis/oh/internal/common/validate"
 	"github.com/michaelmacinnis/oh/internal/engine/commands"
 	"github.com/michaelmacinnis/oh/internal/system/cache"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
 )
 
 // Action performs a single step of the machine and returns the next operation.
@@ -42,18 +49,27 @@ func (a Action) Perform(t *T) Op {
 // Actions associates actions with names in the scope s.
 func Actions(s scope.I) {
 	// Base.
+	s.Define("arithmetic", &Syntax{Op: Action(arithmetic)})
 	s.Define("block", &Syntax{Op: Action(block)})
 	s.Define("define", &Syntax{Op: Action(evalDefine)})
 	s.Define("if", &Syntax{Op: Action(evalIf)})
+	s.Define("import", &Syntax{Op: Action(evalImport)})
 	s.Define("while", &Syntax{Op: Action(evalWhile)})
 	s.Define("set", &Syntax{Op: Action(evalSet)})
 	s.Define("spawn", &Syntax{Op: Action(spawn)})
+	s.Define("try", &Syntax{Op: Action(evalTry)})
 
 	s.Define("get", &Method{Op: Action(get)})
 	s.Define("eval", &Method{Op: Action(eval)})
 	s.Define("set?", &Method{Op: Action(isSet)})
 	s.Define("unset", &Method{Op: Action(unset)})
 
+	s.Define("defined-here?", &Method{Op: Action(isDefinedHere)})
+	s.Define("describe", &Method{Op: Action(describe)})
+	s.Define("enclosing", &Method{Op: Action(enclosing)})
+	s.Define("members", &Method{Op: Action(members)})
+	s.Define("public-members", &Method{Op: Action(publicMembers)})
+
 	// Builtins.
 	s.Define("cd", &Method{Op: Action(cd)})
 	s.Define("command", &Method{Op: Action(cmd)})
@@ -67,10 +83,14 @@ func Actions(s scope.I) {
 
 	// Methods.
 	s.Define("continuation?", &Method{Op: Action(isContinuation)})
+	s.Define("exception", &Method{Op: Action(makeException)})
 	s.Define("exit", &Method{Op: Action(exit)})
 	s.Define("fatal", &Method{Op: Action(fatal)})
 	s.Define("interpolate", &Method{Op: Action(interpolate)})
 	s.Define("method?", &Method{Op: Action(isMethod)})
+	s.Define("parameter", &Method{Op: Action(parameter)})
+	s.Define("parse-file", &Method{Op: Action(parseFile)})
+	s.Define("process-id", &Method{Op: Action(processID)})
 	s.Define("resolve", &Method{Op: Action(resolve)})
 	s.Define("resolves?", &Method{Op: Action(resolves)})
 	s.Define("splice", &Method{Op: Action(splice)})
@@ -125,6 +145,42 @@ func EvalExport(t *T) Op {
 	return t.PushOp(Action(evalArg))
 }
 
+// BytesScope returns the 'bytes' object/module containing all bytes methods.
+func BytesScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.BytesFunctions() {
+		s.Export(k, f(v))
+	}
+
+	return obj.New(s)
+}
+
+// MathScope returns the 'num' object/module containing all math methods.
+func MathScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.MathFunctions() {
+		s.Export(k, f(v))
+	}
+
+	return obj.New(s)
+}
+
+// RegexScope returns the 're' object/module containing all regular
+// expression methods.
+func RegexScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.RegexFunctions() {
+		s.Export(k, f(v))
+	}
+
+	s.Export("replace", &Method{Op: Action(regexReplace)})
+
+	return obj.New(s)
+}
+
 // StringScope returns the 'str' object/module containing all string methods.
 func StringScope() scope.I {
 	s := env.New(nil)
@@ -136,6 +192,19 @@ func StringScope() scope.I {
 	return obj.New(s)
 }
 
+// TimeScope returns the 'time' object/module containing all time methods.
+func TimeScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.TimeFunctions() {
+		s.Export(k, f(v))
+	}
+
+	s.Export("sleep", &Method{Op: Action(sleep)})
+
+	return obj.New(s)
+}
+
 // All commands are bound to the scope in which they were found.
 type binding struct {
 	command
@@ -145,6 +214,7 @@ type binding struct {
 // Method, and syntax types all conform to the command interface.
 type command interface {
 	cell.I
+	fmt.Formatter
 
 	Closure() *Closure
 	Execute(*T) Op
@@ -152,8 +222,12 @@ type command interface {
 
 //nolint:gochecknoglobals
 var (
-	conduitScope = makeConduitScope()
-	listScope    = makeListScope()
+	conduitScope   = makeConduitScope()
+	exceptionScope = makeExceptionScope()
+	listScope      = makeListScope()
+	mapScope       = makeMapScope()
+	pipeScope      = makePipeScope()
+	pipelineScope  = makePipelineScope()
 )
 
 // accessMember looks for a command named Name in the object Object.
@@ -174,7 +248,7 @@ func accessMember(t *T) Op {
 	o := t.Result()
 
 	if !sym.Is(m) {
-		panic("member name must be a symbol not a " + m.Name())
+		panic(exception.New(exception.TypeError, "member name must be a symbol not a "+m.Name()))
 	}
 
 	n := literal.String(m)
@@ -184,23 +258,31 @@ func accessMember(t *T) Op {
 	switch o := o.(type) {
 	case scope.I:
 		r = o.Lookup(n)
+	case *pipe.T:
+		r = pipeScope.Lookup(n)
 	case conduit.I:
 		r = conduitScope.Lookup(n)
+	case *exception.T:
+		r = exceptionScope.Lookup(n)
+	case *hmap.T:
+		r = mapScope.Lookup(n)
 	case *pair.T:
 		r = listScope.Lookup(n)
+	case *pipeline.T:
+		r = pipelineScope.Lookup(n)
 	default:
-		panic(m.Name() + " is not an object")
+		panic(exception.New(exception.TypeError, m.Name()+" is not an object"))
 	}
 
 	if r == nil {
-		panic("'" + n + "' not defined")
+		panic(exception.New(exception.NotDefined, "'"+n+"' not defined"))
 	}
 
 	v := r.Get()
 
 	c, ok := v.(command)
 	if !ok {
-		panic(n + " is not executable")
+		panic(exception.New(exception.TypeError, n+" is not executable"))
 	}
 
 	t.ReplaceResult(bind(c, o))
@@ -259,6 +341,17 @@ func apply(t *T) Op {
 	args := t.code
 	plabels := c.Labels.Params
 
+	if c.Labels.Options != nil {
+		var help bool
+
+		args, help = c.Labels.keywords(e, args)
+		if help {
+			t.code = list.New(list.New(sym.New("echo"), str.New("usage: "+c.Labels.usage())))
+
+			return t.PushOp(Action(evalBlock))
+		}
+	}
+
 	actual := int(list.Length(args))
 	expected := int(list.Length(plabels))
 
@@ -268,15 +361,24 @@ func apply(t *T) Op {
 			break
 		}
 
-		e.Define(literal.String(label), pair.Car(args))
+		k := literal.String(label)
+		policy.Variable(k)
+
+		e.Define(k, pair.Car(args))
 		args, plabels = pair.Cdr(args), pair.Cdr(plabels)
 	}
 
 	rest := pair.Car(plabels)
 	if plabels != pair.Null && pair.Is(rest) && pair.Cdr(rest) == pair.Null {
-		e.Define(literal.String(pair.Car(rest)), args)
+		k := literal.String(pair.Car(rest))
+		policy.Variable(k)
+
+		e.Define(k, args)
 	} else if actual != expected {
-		panic("expected " + validate.Count(expected, "argument", "s") + ", passed " + strconv.Itoa(actual))
+		panic(exception.New(
+			exception.TypeError,
+			"expected "+validate.Count(expected, "argument", "s")+", passed "+strconv.Itoa(actual),
+		))
 	}
 
 	t.code = c.Body
@@ -313,11 +415,15 @@ func block(t *T) Op {
 func continuation(t *T) Op {
 	r := t.PopResult()
 
-	t.Result().(*registers).restoreOver(t.registers)
+	k := t.Result().(*registers)
 
-	t.ReplaceResult(r)
+	return t.unwind(k.stack, Action(func(t *T) Op {
+		k.restoreOver(t.registers)
 
-	return t.Op()
+		t.ReplaceResult(r)
+
+		return t.Op()
+	}))
 }
 
 // eval evaluates its argument in the scope provided by self.
@@ -552,6 +658,47 @@ func evalSet(t *T) Op {
 	return t.PushOp(Action(evalArg))
 }
 
+// evalTry evaluates a block of code in a new scope and then, however the
+// block is exited, the block of code following finally, if any.
+//
+// Result:
+//  code:  Cmd_0 ... Cmd_N
+//  dump:  Null ...
+//  frame: New scope
+//  stack: evalBlock Cleanup(Block) Restore(frame: Current) Previous ...
+//
+// Requires:
+//  code:  Cmd_0 ... Cmd_N [finally Block]
+//  dump:  Binding ...
+//  frame: Current
+//  stack: try Previous ...
+//
+func evalTry(t *T) Op {
+	body := []cell.I{}
+
+	c := t.code
+	for ; c != pair.Null && pair.Is(pair.Car(c)); c = pair.Cdr(c) {
+		body = append(body, pair.Car(c))
+	}
+
+	t.ReplaceOp(&registers{frame: t.frame})
+
+	if c != pair.Null {
+		if literal.String(pair.Car(c)) != "finally" {
+			panic(exception.New(exception.Syntax, "expected finally"))
+		}
+
+		t.PushOp(&cleanup{code: pair.Cdr(c), frame: t.frame})
+	}
+
+	t.code = list.New(body...)
+	t.frame = frame.Dup(env.New(t.frame.Scope()), t.frame)
+
+	t.ReplaceResult(pair.Null)
+
+	return t.PushOp(Action(evalBlock))
+}
+
 // evalWhile creates new scope in which to execute a while-loop.
 //
 // Result:
@@ -596,7 +743,7 @@ func execCommand(t *T) Op {
 	r := t.Result()
 
 	switch v := r.(type) {
-	case scope.I, conduit.I, *pair.T:
+	case scope.I, conduit.I, *exception.T, *hmap.T, *pair.T, *pipeline.T:
 		t.PushOp(&registers{code: pair.Cdr(t.code)})
 		t.code = pair.Car(t.code)
 		t.PushOp(Action(accessMember))
@@ -631,6 +778,8 @@ func execDefine(t *T) Op {
 	k := literal.String(t.PopResult())
 	b := bound(t.Result())
 
+	policy.Variable(k)
+
 	scope.To(b.self).Define(k, v)
 
 	return t.Return(v)
@@ -641,6 +790,8 @@ func execExport(t *T) Op {
 	k := literal.String(t.PopResult())
 	b := bound(t.Result())
 
+	policy.Variable(k)
+
 	scope.To(b.self).Export(k, v)
 
 	return t.Return(v)
@@ -670,7 +821,7 @@ func execIfBody(t *T) Op {
 		}
 
 		if c != pair.Null && literal.String(c) != "else" {
-			panic("expected else")
+			panic(exception.New(exception.Syntax, "expected else"))
 		}
 	}
 
@@ -682,7 +833,7 @@ func execIfBody(t *T) Op {
 	next := pair.Car(t.code)
 	if alternate && !pair.Is(next) {
 		if literal.String(next) != "if" {
-			panic("expected if")
+			panic(exception.New(exception.Syntax, "expected if"))
 		}
 
 		return t.ReplaceOp(Action(EvalCommand))
@@ -733,11 +884,13 @@ func execSet(t *T) Op {
 
 	b := bound(t.Result())
 
+	policy.Variable(k)
+
 	s := scope.To(b.self)
 	r := s.Lookup(k)
 
 	if r == nil {
-		panic("'" + k + "' not defined")
+		panic(exception.New(exception.NotDefined, "'"+k+"' not defined"))
 	}
 
 	r.Set(v)
@@ -803,35 +956,49 @@ func external(t *T) Op {
 	args := t.expand(t.code)
 
 	name := common.String(pair.Car(args))
+	dir := t.stringValue("PWD")
+	sb := t.sandbox()
+
+	var (
+		arg0       string
+		executable bool
+		err        error
+	)
+
+	if sb == nil {
+		arg0, executable, err = adapted.LookPath(name, t.stringValue("PATH"))
+	} else {
+		arg0, executable, err = sb.LookPath(name, t.stringValue("PATH"), dir)
+	}
 
-	arg0, executable, err := adapted.LookPath(name, t.stringValue("PATH"))
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.ExecFailed, err))
 	}
 
-	cache.Check(arg0)
+	if sb == nil {
+		cache.Check(arg0)
+	}
 
 	if !executable {
 		return t.Return(t.Chdir(name))
 	}
 
+	policy.Command(name, arg0)
+
 	argv := []string{name}
 	for args = pair.Cdr(args); args != pair.Null; args = pair.Cdr(args) {
 		argv = append(argv, common.String(pair.Car(args)))
 	}
 
-	dir := t.stringValue("PWD")
-	stdin := t.value(nil, "stdin")
-	stdout := t.value(nil, "stdout")
-	stderr := t.value(nil, "stderr")
-
-	files := []*os.File{pipe.R(stdin), pipe.W(stdout), pipe.W(stderr)}
+	if sb != nil {
+		dir = sb.Dir(dir)
+	}
 
-	attr := &os.ProcAttr{Dir: dir, Env: t.Environ(), Files: files}
+	attr := &os.ProcAttr{Dir: dir, Env: t.Environ(), Files: t.files()}
 
-	err = t.job.Execute(t, arg0, argv, attr)
+	err = t.job.Execute(t, arg0, argv, attr, sb)
 	if err != nil {
-		panic(err.Error())
+		panic(exception.Wrap(exception.ExecFailed, err))
 	}
 
 	return t.PreviousOp()
@@ -903,7 +1070,7 @@ func spawn(t *T) Op {
 
 	child.PushOp(Action(evalBlock))
 
-	t.job.Spawn(t, child, nil)
+	t.job.Spawn(t, child, policy.Spawned())
 
 	return t.Return(child)
 }
@@ -959,7 +1126,7 @@ func bound(a cell.I) *binding {
 		return b
 	}
 
-	panic(a.Name() + " is not a command")
+	panic(exception.New(exception.TypeError, a.Name()+" is not a command"))
 }
 
 func device(m os.FileMode) bool {
@@ -976,6 +1143,16 @@ func makeConduitScope() scope.I {
 	return obj.New(s)
 }
 
+func makeExceptionScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.ExceptionMethods() {
+		s.Export(k, m(v))
+	}
+
+	return obj.New(s)
+}
+
 func makeListScope() scope.I {
 	s := env.New(nil)
 
@@ -983,9 +1160,56 @@ func makeListScope() scope.I {
 		s.Export(k, m(v))
 	}
 
+	for k, v := range listActions() {
+		s.Export(k, &Method{Op: v})
+	}
+
+	return obj.New(s)
+}
+
+func makeMapScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.MapMethods() {
+		s.Export(k, m(v))
+	}
+
 	return obj.New(s)
 }
 
+func makePipeScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.ConduitMethods() {
+		s.Export(k, m(v))
+	}
+
+	for k, v := range commands.PipeMethods() {
+		s.Export(k, m(v))
+	}
+
+	return obj.New(s)
+}
+
+func makePipelineScope() scope.I {
+	s := env.New(nil)
+
+	for k, v := range commands.PipelineMethods() {
+		s.Export(k, m(v))
+	}
+
+	return obj.New(s)
+}
+
+func names(s []string) cell.I {
+	l := make([]cell.I, len(s))
+	for i, n := range s {
+		l[i] = sym.New(n)
+	}
+
+	return list.New(l...)
+}
+
 // Builtins.
 
 func cd(t *T) Op {
@@ -1053,6 +1277,50 @@ func glob(t *T) Op {
 
 // Methods.
 
+func describe(t *T) Op {
+	validate.Fixed(t.code, 0, 0)
+
+	s := scope.To(bound(t.Result()).self)
+
+	kvs := []cell.I{}
+
+	for _, k := range s.Members() {
+		kind := "value"
+
+		v := s.Lookup(k).Get()
+		if b, ok := v.(*binding); ok {
+			v = b.command
+		}
+
+		switch v.(type) {
+		case *registers:
+			kind = "continuation"
+		case *Method:
+			kind = "method"
+		case *Syntax:
+			kind = "syntax"
+		}
+
+		kvs = append(kvs, sym.New(k), sym.New(kind))
+	}
+
+	return t.Return(hmap.New(kvs...))
+}
+
+// Objects are not a way to reach the scope in which they were created.
+func enclosing(t *T) Op {
+	validate.Fixed(t.code, 0, 0)
+
+	s := scope.To(bound(t.Result()).self)
+
+	e := s.Enclosing()
+	if e == nil || obj.Is(s) {
+		return t.Return(pair.Null)
+	}
+
+	return t.Return(e)
+}
+
 func exit(t *T) Op {
 	t.Exit()
 
@@ -1068,11 +1336,13 @@ func fatal(t *T) Op {
 		c = v[0]
 	}
 
-	t.stack = done
+	return t.unwind(done, Action(func(t *T) Op {
+		t.stack = done
 
-	t.ReplaceResult(c)
+		t.ReplaceResult(c)
 
-	return t.Op()
+		return t.Op()
+	}))
 }
 
 func get(t *T) Op {
@@ -1084,7 +1354,7 @@ func get(t *T) Op {
 
 	r := s.Lookup(k)
 	if r == nil {
-		panic("'" + k + "' not defined")
+		panic(exception.New(exception.NotDefined, "'"+k+"' not defined"))
 	}
 
 	v := r.Get()
@@ -1098,33 +1368,9 @@ func get(t *T) Op {
 func interpolate(t *T) Op {
 	v := validate.Fixed(t.code, 1, 1)
 
-	b := bound(t.Result())
-
-	s := scope.To(b.self)
-
-	cb := func(ref string) string {
-		if ref == "$$" {
-			return "$"
-		}
-
-		name := ref[1:]
-		if name[0] == '{' {
-			name = name[1 : len(name)-1]
-		}
-
-		return common.String(t.resolve(s, name))
-	}
-	// '!', '%', '*', '+', '-',
-	// '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
-	// '?', '@',
-	// 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M',
-	// 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z',
-	// '[', ']', '^', '_',
-	// 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm',
-	// 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z',
-	r := regexp.MustCompile(`(?:\$\$)|(?:\${.+?})|(?:\$[!%*+\-0-9?@A-Z\[\]^_a-z]+)`)
+	s := scope.To(bound(t.Result()).self)
 
-	return t.Return(str.New(r.ReplaceAllStringFunc(common.String(v[0]), cb)))
+	return t.Return(str.New(t.interpolated(s, common.String(v[0]))))
 }
 
 func isContinuation(t *T) Op {
@@ -1135,6 +1381,18 @@ func isContinuation(t *T) Op {
 	return t.Return(create.Bool(ok))
 }
 
+func isDefinedHere(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	k := literal.String(v[0])
+
+	members := scope.To(bound(t.Result()).self).Members()
+
+	i := sort.SearchStrings(members, k)
+
+	return t.Return(create.Bool(i < len(members) && members[i] == k))
+}
+
 func isMethod(t *T) Op {
 	v := validate.Fixed(t.code, 1, 1)
 
@@ -1169,6 +1427,85 @@ func isSyntax(t *T) Op {
 	return t.Return(create.Bool(ok))
 }
 
+// MakeException creates an exception with a kind, a message and, optionally,
+// the value that caused it. The exception is raised by the caller.
+func makeException(t *T) Op {
+	v := validate.Fixed(t.code, 2, 3)
+
+	e := exception.New(common.String(v[0]), common.String(v[1]))
+	if len(v) == 3 && v[2] != pair.Null { //nolint:gomnd
+		e.Because(v[2])
+	}
+
+	return t.Return(e.Raised(t.frame.Loc(), t.backtrace(false)))
+}
+
+func members(t *T) Op {
+	validate.Fixed(t.code, 0, 0)
+
+	return t.Return(names(scope.To(bound(t.Result()).self).Members()))
+}
+
+func processID(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	c, ok := v[0].(*T)
+	if !ok {
+		panic(exception.New(exception.TypeError, "can't get process ID for "+v[0].Name()))
+	}
+
+	pid := c.PID()
+	if pid == 0 {
+		return t.Return(pair.Null)
+	}
+
+	return t.Return(num.Int(pid))
+}
+
+func publicMembers(t *T) Op {
+	validate.Fixed(t.code, 0, 0)
+
+	return t.Return(names(scope.To(bound(t.Result()).self).Public().Keys()))
+}
+
+// regexReplace replaces each match of a pattern in a string. The
+// replacement is either a string, which may refer to groups as $1 or
+// ${name}, or a method. A method is called with the text of the match
+// followed by the text of each group and returns the replacement text.
+func regexReplace(t *T) Op {
+	v := validate.Fixed(t.code, 3, 3)
+
+	r := regex.Compile(v[0])
+	s := common.String(v[1])
+
+	c, ok := v[2].(command)
+	if !ok {
+		return t.Return(str.New(r.ReplaceAllString(s, common.String(v[2]))))
+	}
+
+	return replaceNext(t, c, s, r.FindAllStringSubmatchIndex(s, -1), 0, &strings.Builder{})
+}
+
+func replaceNext(t *T, c command, s string, matches [][]int, end int, b *strings.Builder) Op {
+	if len(matches) == 0 {
+		b.WriteString(s[end:])
+
+		return t.Return(str.New(b.String()))
+	}
+
+	loc := matches[0]
+
+	b.WriteString(s[end:loc[0]])
+
+	t.ReplaceOp(Action(func(t *T) Op {
+		b.WriteString(common.String(t.PopResult()))
+
+		return replaceNext(t, c, s, matches[1:], loc[1], b)
+	}))
+
+	return t.call(c, commands.Submatches(s, loc))
+}
+
 func resolve(t *T) Op {
 	k := literal.String(pair.Car(t.code))
 
@@ -1191,12 +1528,38 @@ func resolves(t *T) Op {
 	return t.Return(create.Bool(v != nil))
 }
 
+// Sleep waits for a duration. Like a task waiting on other tasks, a
+// sleeping task is waiting and so can be stopped or interrupted.
+func sleep(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	d := commands.Duration(v[0])
+
+	t.Wait()
+
+	time.AfterFunc(d, func() {
+		t.Notify(sym.True)
+	})
+
+	return t.ReplaceOp(Action(resume))
+}
+
 func trace(t *T) Op {
+	return t.Return(t.backtrace(false))
+}
+
+// Backtrace returns a list of the locations, outermost first, that lead to
+// the current location and, if current is true, the current location.
+func (t *T) backtrace(current bool) cell.I {
 	dup := *t.registers
 
 	l := dup.frame.Loc()
 	trace := []cell.I{}
 
+	if current && !strings.HasSuffix(l.Text, "# oh:omit-from-trace") {
+		trace = append(trace, str.New(l.String()+": "+l.Text))
+	}
+
 	for dup.stack != done {
 		r, ok := dup.Op().(*registers)
 		if !ok {
@@ -1219,12 +1582,14 @@ func trace(t *T) Op {
 		dup.PreviousOp()
 	}
 
-	return t.Return(list.Reverse(list.New(trace...)))
+	return list.Reverse(list.New(trace...))
 }
 
 func unset(t *T) Op {
 	k := literal.String(pair.Car(t.code))
 
+	policy.Variable(k)
+
 	b := bound(t.Result())
 
 	s := scope.To(b.self)
@@ -1242,7 +1607,7 @@ func wait(t *T) Op {
 
 		t, ok := c.(*T)
 		if !ok {
-			panic("can't wait on " + c.Name())
+			panic(exception.New(exception.TypeError, "can't wait on "+c.Name()))
 		}
 
 		v = append(v, t)
@@ -0,0 +1,130 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"math/big"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/interface/rational"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+)
+
+// The value of a node in an arithmetic expression. Like the arithmetic
+// commands, the result of a calculation involving an inexact number is
+// inexact.
+type value struct {
+	r       *big.Rat
+	inexact bool
+}
+
+// arithmetic evaluates the expression tree produced by the parser for an
+// arithmetic expansion, $(( ... )). Comparison and logical operators
+// produce 1 for true and 0 for false. The logical operators && and || only
+// evaluate their second operand if necessary.
+func arithmetic(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	n := t.calculate(v[0])
+	if n.inexact {
+		return t.Return(num.Inexact(n.r))
+	}
+
+	return t.Return(num.Rat(n.r))
+}
+
+func (t *T) calculate(c cell.I) value {
+	if !pair.Is(c) {
+		s := literal.String(c)
+		if s != "" && (s[0] == '.' || '0' <= s[0] && s[0] <= '9') {
+			return number(c)
+		}
+
+		return number(t.resolve(nil, s))
+	}
+
+	v := list.Array(c)
+	op := literal.String(v[0])
+
+	a := t.calculate(v[1])
+
+	switch op {
+	case "neg":
+		return value{new(big.Rat).Neg(a.r), a.inexact}
+	case "not":
+		return truth(a.r.Sign() == 0)
+	case "and":
+		if a.r.Sign() == 0 {
+			return truth(false)
+		}
+
+		return truth(t.calculate(v[2]).r.Sign() != 0)
+	case "or":
+		if a.r.Sign() != 0 {
+			return truth(true)
+		}
+
+		return truth(t.calculate(v[2]).r.Sign() != 0)
+	}
+
+	b := t.calculate(v[2])
+
+	r := new(big.Rat)
+	inexact := a.inexact || b.inexact
+
+	switch op {
+	case "add":
+		r.Add(a.r, b.r)
+	case "sub":
+		r.Sub(a.r, b.r)
+	case "mul":
+		r.Mul(a.r, b.r)
+	case "div":
+		if b.r.Sign() == 0 {
+			panic(exception.New(exception.Arithmetic, "division by zero"))
+		}
+
+		r.Quo(a.r, b.r)
+	case "mod":
+		if !a.r.IsInt() || !b.r.IsInt() || b.r.Sign() == 0 {
+			panic(exception.New(exception.Arithmetic, "% requires integers and a non-zero divisor"))
+		}
+
+		r.SetInt(new(big.Int).Mod(a.r.Num(), b.r.Num()))
+	case "eq":
+		return truth(a.r.Cmp(b.r) == 0)
+	case "ne":
+		return truth(a.r.Cmp(b.r) != 0)
+	case "lt":
+		return truth(a.r.Cmp(b.r) < 0)
+	case "le":
+		return truth(a.r.Cmp(b.r) <= 0)
+	case "gt":
+		return truth(a.r.Cmp(b.r) > 0)
+	case "ge":
+		return truth(a.r.Cmp(b.r) >= 0)
+	default:
+		panic(exception.New(exception.Syntax, "unknown arithmetic operator '"+op+"'"))
+	}
+
+	return value{r, inexact}
+}
+
+func number(c cell.I) value {
+	n, ok := c.(*num.T)
+
+	return value{rational.Number(c), ok && n.Inexact()}
+}
+
+func truth(b bool) value {
+	if b {
+		return value{big.NewRat(1, 1), false}
+	}
+
+	return value{new(big.Rat), false}
+}
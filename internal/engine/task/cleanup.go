@@ -0,0 +1,91 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
+	"github.com/michaelmacinnis/oh/internal/common/type/env"
+)
+
+// The cleanup type is an operation that evaluates a block of code, or calls
+// a Go function, when it is reached. A cleanup operation is reached either
+// when the operations above it complete or when the stack is unwound past it.
+type cleanup struct {
+	code  cell.I
+	frame *frame.T
+	f     func()
+}
+
+// Perform evaluates the cleanup block in a new scope and then restores
+// the result that was current when the cleanup operation was reached.
+func (c *cleanup) Perform(t *T) Op {
+	if c.f != nil {
+		c.f()
+
+		return t.PreviousOp()
+	}
+
+	r := t.Result()
+
+	t.ReplaceOp(Action(func(t *T) Op {
+		return t.Return(r)
+	}))
+
+	t.PushOp(&registers{code: t.code, frame: t.frame})
+
+	t.code = c.code
+	t.frame = frame.Dup(env.New(c.frame.Scope()), c.frame)
+
+	return t.PushOp(Action(evalBlock))
+}
+
+// Unwind replaces the current stack with s and arranges for the cleanup
+// operations that are skipped as a result to be performed, innermost
+// first, before next. A nil stack does not replace the current stack.
+// The skipped stack is discarded before any cleanup is performed and each
+// pending cleanup operation is removed from the stack as it is performed
+// so, if one of them unwinds the stack, it is not performed again but the
+// rest are still pending.
+func (t *T) unwind(s *stack, next Op) Op {
+	pending := []*cleanup{}
+
+	for p := t.stack; s != nil && p != done; p = p.stack {
+		if c, ok := p.op.(*cleanup); ok {
+			pending = append(pending, c)
+		}
+	}
+
+	if len(pending) > 0 {
+		kept := map[Op]bool{}
+
+		for p := s; p != done; p = p.stack {
+			if c, ok := p.op.(*cleanup); ok {
+				kept[c] = true
+			}
+		}
+
+		skipped := pending[:0]
+
+		for _, c := range pending {
+			if !kept[c] {
+				skipped = append(skipped, c)
+			}
+		}
+
+		pending = skipped
+	}
+
+	if s != nil {
+		t.stack = s
+		t.PushOp(next)
+	} else {
+		t.ReplaceOp(next)
+	}
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		t.PushOp(pending[i])
+	}
+
+	return t.Op()
+}
@@ -17,7 +17,8 @@ type Closure struct {
 
 // Labels hold the labels for a user-defined routine.
 type Labels struct {
-	Env    cell.I // Calling env label.
-	Params cell.I // Param labels.
-	Self   cell.I // Label for the env where this routine was found.
+	Env     cell.I    // Calling env label.
+	Options []*Option // Keyword parameters.
+	Params  cell.I    // Param labels.
+	Self    cell.I    // Label for the env where this routine was found.
 }
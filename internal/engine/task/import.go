@@ -0,0 +1,292 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
+	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
+	"github.com/michaelmacinnis/oh/internal/common/type/env"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/obj"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/michaelmacinnis/oh/internal/reader"
+	"github.com/michaelmacinnis/oh/internal/system/mod"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
+)
+
+// A module is the object created by evaluating a file and the modification
+// time of the file when it was evaluated. While a module is being evaluated
+// its object is nil and ready is closed when the evaluation ends.
+type module struct {
+	mtime  time.Time
+	object scope.I
+	ready  chan struct{}
+}
+
+// Modules are cached by canonical path. Tasks that import a module while
+// it is being evaluated wait for that evaluation rather than starting
+// their own.
+//
+//nolint:gochecknoglobals
+var modules = struct {
+	sync.Mutex
+	cache map[string]module
+}{cache: map[string]module{}}
+
+// The importing type marks the part of the stack where a module is being
+// evaluated. Walking the stack for these markers gives the chain of imports
+// that lead to the current module.
+type importing struct {
+	name string
+	path string
+}
+
+// Perform does nothing. The marker is simply removed.
+func (i *importing) Perform(t *T) Op {
+	return t.PreviousOp()
+}
+
+// evalImport evaluates the name of the module to import and, if there is
+// an only clause, the keyword that starts it.
+func evalImport(t *T) Op {
+	rest := pair.Cdr(t.code)
+	if rest == pair.Null {
+		t.ReplaceOp(Action(execImport))
+	} else {
+		t.ReplaceOp(Action(execImportOnly))
+		t.PushOp(&registers{code: pair.Cdr(rest)})
+		t.PushOp(Action(evalArg))
+		t.PushOp(&registers{code: pair.Car(rest)})
+	}
+
+	t.code = pair.Car(t.code)
+
+	return t.PushOp(Action(evalArg))
+}
+
+func execImport(t *T) Op {
+	return t.load(nil)
+}
+
+// The names in an only clause are either the names following "only:" or
+// a list of names following "only".
+func execImportOnly(t *T) Op {
+	k := literal.String(t.PopResult())
+
+	switch {
+	case k == "only:":
+	case k == "only" && pair.Is(pair.Car(t.code)) && pair.Cdr(t.code) == pair.Null:
+		t.code = pair.Car(t.code)
+	default:
+		panic(exception.New(exception.TypeError, "expected only: name ..."))
+	}
+
+	names := []string{}
+
+	for l := t.code; l != pair.Null; l = pair.Cdr(l) {
+		names = append(names, literal.String(pair.Car(l)))
+	}
+
+	return t.load(names)
+}
+
+// Load returns the module object for a file, evaluating the file if it has
+// not been evaluated or if it has changed since it was evaluated. Each file
+// is evaluated in a new scope, enclosed by the root scope, where ORIGIN is
+// the directory that contains the file. Names listed after only are also
+// defined in the importer's scope.
+func (t *T) load(names []string) Op {
+	name := common.String(t.PopResult())
+	b := bound(t.Result())
+
+	path, mtime := t.locate(name)
+
+	t.cycle(name, path)
+
+	ready := make(chan struct{})
+
+	for {
+		modules.Lock()
+		m, ok := modules.cache[path]
+
+		if !ok || m.object != nil && !m.mtime.Equal(mtime) {
+			modules.cache[path] = module{mtime: mtime, ready: ready}
+			modules.Unlock()
+
+			break
+		}
+		modules.Unlock()
+
+		if m.object != nil {
+			return t.Return(imported(t.frame.Scope(), name, m.object, names))
+		}
+
+		<-m.ready
+	}
+
+	// However the evaluation ends, waiting tasks are released. If the
+	// module was not cached, one of them will evaluate it. A continuation
+	// may reach this cleanup more than once.
+	var once sync.Once
+
+	t.ReplaceOp(&cleanup{f: func() {
+		once.Do(func() {
+			modules.Lock()
+			if modules.cache[path].ready == ready {
+				delete(modules.cache, path)
+			}
+			modules.Unlock()
+
+			close(ready)
+		})
+	}})
+
+	code := parse(path, path)
+
+	// Modules are evaluated in the root scope, not the importer's, so
+	// that the importer's variables are not visible to the module.
+	root := scope.To(b.self)
+	for root.Enclosing() != nil {
+		root = root.Enclosing()
+	}
+
+	s := env.New(root)
+	s.Define("ORIGIN", sym.New(filepath.Dir(path)))
+
+	o := obj.New(s)
+
+	t.PushOp(Action(func(t *T) Op {
+		modules.Lock()
+		modules.cache[path] = module{mtime: mtime, object: o}
+		modules.Unlock()
+
+		return t.Return(imported(t.frame.Scope(), name, o, names))
+	}))
+
+	t.PushOp(&importing{name: name, path: path})
+	t.PushOp(&registers{code: t.code, frame: t.frame})
+
+	t.code = code
+	t.frame = frame.New(s, t.frame)
+
+	return t.PushOp(Action(evalBlock))
+}
+
+// Cycle panics if the module at path is already being imported.
+func (t *T) cycle(name, path string) {
+	chain := []string{name}
+	found := false
+
+	for p := t.stack; p != done && !found; p = p.stack {
+		if i, ok := p.op.(*importing); ok {
+			chain = append(chain, i.name)
+			found = i.path == path
+		}
+	}
+
+	if !found {
+		return
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	panic(exception.New(exception.Import, "import cycle: "+strings.Join(chain, " -> ")))
+}
+
+// Imported defines each of names, which must be public members of the
+// module object o, in the scope s and returns o.
+func imported(s scope.I, name string, o scope.I, names []string) cell.I {
+	for _, k := range names {
+		r := o.Lookup(k)
+		if r == nil {
+			panic(exception.New(exception.NotDefined, "'"+k+"' not exported by "+name))
+		}
+
+		s.Define(k, r.Get())
+	}
+
+	return o
+}
+
+// Locate returns the canonical path and modification time of the file for
+// a module. Relative names are resolved relative to the importer's ORIGIN,
+// then the oh_modules directories of the projects that contain ORIGIN, and
+// then each directory in OHPATH.
+func (t *T) locate(name string) (string, time.Time) {
+	name = t.tildeExpand(name)
+
+	dirs := []string{""}
+	if !filepath.IsAbs(name) {
+		origin := t.stringValue("ORIGIN")
+
+		dirs = append([]string{origin}, mod.Paths(origin)...)
+		dirs = append(dirs, filepath.SplitList(t.stringValue("OHPATH"))...)
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(t.stringValue("PWD"), path)
+		}
+
+		path, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		return path, info.ModTime()
+	}
+
+	panic(exception.New(exception.Import, "module '"+name+"' not found"))
+}
+
+// Parse returns the commands in the file at path. Locations are labelled
+// with name.
+func parse(name, path string) cell.I {
+	policy.Open(path, true, false)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		panic(exception.Wrap(exception.IO, err))
+	}
+
+	cs, err := reader.Parse(name, string(b))
+	if err != nil {
+		panic(exception.Wrap(exception.Syntax, err))
+	}
+
+	return list.New(cs...)
+}
+
+// parseFile returns the commands in a file as a list. It is used by source.
+func parseFile(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	name := common.String(v[0])
+
+	path := t.tildeExpand(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.stringValue("PWD"), path)
+	}
+
+	return t.Return(parse(name, path))
+}
@@ -0,0 +1,306 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"sort"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/michaelmacinnis/oh/internal/engine/commands"
+)
+
+// The list methods below call back into oh. Each call is made by pushing
+// operations rather than by recursing on the Go stack so that these
+// methods work like any other oh code: they can be interrupted, throw
+// exceptions and capture continuations. Results are accumulated in values
+// that are never modified, only replaced, so that a continuation captured
+// by a callback can be resumed more than once.
+
+func listActions() map[string]Action {
+	return map[string]Action{
+		"all?":      isAll,
+		"any?":      isAny,
+		"filter":    filter,
+		"find":      find,
+		"fold":      fold,
+		"group-by":  groupBy,
+		"partition": partition,
+		"reduce":    reduce,
+		"sort":      sortList,
+		"sort-by":   sortBy,
+	}
+}
+
+func filter(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, pair.Null, func(kept, e, r cell.I) (cell.I, bool) {
+		if boolean.Value(r) {
+			kept = pair.Cons(e, kept)
+		}
+
+		return kept, true
+	}, list.Reverse)
+}
+
+func find(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, pair.Null, func(found, e, r cell.I) (cell.I, bool) {
+		if boolean.Value(r) {
+			return e, false
+		}
+
+		return found, true
+	}, nil)
+}
+
+// Fold combines elements, from left to right, by calling a method with the
+// value so far, starting with the initial value, and the next element.
+func fold(t *T) Op {
+	v := validate.Fixed(t.code, 2, 2)
+
+	l := list.Array(bound(t.Result()).self)
+
+	return t.fold(executable(v[1]), v[0], l)
+}
+
+// GroupBy returns a map from the key for each element to a list of the
+// elements with that key. Elements keep their relative order.
+func groupBy(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, pair.Null, func(pairs, e, k cell.I) (cell.I, bool) {
+		return pair.Cons(pair.Cons(k, e), pairs), true
+	}, func(pairs cell.I) cell.I {
+		index := hmap.To(hmap.New())
+		keys := []cell.I{}
+		groups := [][]cell.I{}
+
+		for _, p := range list.Array(list.Reverse(pairs)) {
+			k := pair.Car(p)
+
+			i, ok := index.Get(k)
+			if !ok {
+				i = num.Int(len(keys))
+				index.Set(k, i)
+
+				keys = append(keys, k)
+				groups = append(groups, nil)
+			}
+
+			n := integer.Value(i)
+			groups[n] = append(groups[n], pair.Cdr(p))
+		}
+
+		kvs := make([]cell.I, 0, 2*len(keys)) //nolint:gomnd
+
+		for i, k := range keys {
+			kvs = append(kvs, k, list.New(groups[i]...))
+		}
+
+		return hmap.New(kvs...)
+	})
+}
+
+func isAll(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, create.Bool(true), func(_, _, r cell.I) (cell.I, bool) {
+		result := boolean.Value(r)
+
+		return create.Bool(result), result
+	}, nil)
+}
+
+func isAny(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, create.Bool(false), func(_, _, r cell.I) (cell.I, bool) {
+		result := boolean.Value(r)
+
+		return create.Bool(result), !result
+	}, nil)
+}
+
+// Partition returns a list containing a list of the elements for which
+// the method returns true and a list of the remaining elements.
+func partition(t *T) Op {
+	c, v := callback(t)
+
+	// The lists of elements, in reverse order, are kept in a pair.
+	both := pair.Cons(pair.Null, pair.Null)
+
+	return t.each(c, v, both, func(both, e, r cell.I) (cell.I, bool) {
+		yes, no := pair.Car(both), pair.Cdr(both)
+		if boolean.Value(r) {
+			yes = pair.Cons(e, yes)
+		} else {
+			no = pair.Cons(e, no)
+		}
+
+		return pair.Cons(yes, no), true
+	}, func(both cell.I) cell.I {
+		return list.New(list.Reverse(pair.Car(both)), list.Reverse(pair.Cdr(both)))
+	})
+}
+
+// Reduce is fold with the first element as the initial value.
+func reduce(t *T) Op {
+	c, v := callback(t)
+	if len(v) == 0 {
+		panic(exception.New(exception.Error, "cannot reduce an empty list"))
+	}
+
+	return t.fold(c, v[0], v[1:])
+}
+
+// SortBy sorts elements by the key that a method returns for each element.
+// The sort is stable.
+func sortBy(t *T) Op {
+	c, v := callback(t)
+
+	return t.each(c, v, pair.Null, func(keys, _, k cell.I) (cell.I, bool) {
+		return pair.Cons(k, keys), true
+	}, func(keys cell.I) cell.I {
+		k := list.Array(list.Reverse(keys))
+
+		i := make([]int, len(v))
+		for n := range i {
+			i[n] = n
+		}
+
+		sort.SliceStable(i, func(a, b int) bool {
+			return commands.Less(k[i[a]], k[i[b]])
+		})
+
+		sorted := make([]cell.I, len(v))
+		for n, j := range i {
+			sorted[n] = v[j]
+		}
+
+		return list.New(sorted...)
+	})
+}
+
+// SortList sorts elements in the default order or, if a method is passed,
+// using the method to determine if one element is less than another. The
+// sort is stable.
+func sortList(t *T) Op {
+	v := validate.Fixed(t.code, 0, 1)
+
+	l := list.Array(bound(t.Result()).self)
+
+	if len(v) == 0 {
+		sort.SliceStable(l, func(i, j int) bool {
+			return commands.Less(l[i], l[j])
+		})
+
+		return t.Return(list.New(l...))
+	}
+
+	return t.mergeSort(executable(v[0]), l, func(t *T, sorted []cell.I) Op {
+		return t.Return(list.New(sorted...))
+	})
+}
+
+// Helpers.
+
+// Callback returns the method passed to a list method and the elements of
+// the list.
+func callback(t *T) (command, []cell.I) {
+	v := validate.Fixed(t.code, 1, 1)
+
+	return executable(v[0]), list.Array(bound(t.Result()).self)
+}
+
+func executable(c cell.I) command {
+	if e, ok := c.(command); ok {
+		return e
+	}
+
+	panic(exception.New(exception.TypeError, "expected a method, not a "+c.Name()))
+}
+
+// Each calls c with each element in v, in turn, and passes the value so
+// far, starting with acc, the element and the result to next. Next returns
+// the new value so far and false to stop iterating early. When iteration
+// stops, the value so far, passed through done if done is not nil, is
+// returned.
+func (t *T) each(
+	c command, v []cell.I, acc cell.I,
+	next func(acc, e, r cell.I) (cell.I, bool), done func(acc cell.I) cell.I,
+) Op {
+	if len(v) == 0 {
+		if done != nil {
+			acc = done(acc)
+		}
+
+		return t.Return(acc)
+	}
+
+	t.ReplaceOp(Action(func(t *T) Op {
+		acc, more := next(acc, v[0], t.PopResult())
+		if !more {
+			return t.each(c, nil, acc, next, done)
+		}
+
+		return t.each(c, v[1:], acc, next, done)
+	}))
+
+	return t.call(c, list.New(v[0]))
+}
+
+func (t *T) fold(c command, acc cell.I, v []cell.I) Op {
+	if len(v) == 0 {
+		return t.Return(acc)
+	}
+
+	t.ReplaceOp(Action(func(t *T) Op {
+		return t.fold(c, t.PopResult(), v[1:])
+	}))
+
+	return t.call(c, list.New(acc, v[0]))
+}
+
+// Merge merges the sorted elements l and r. The elements merged so far are
+// kept, in reverse order, in the list out.
+func (t *T) merge(less command, l, r []cell.I, out cell.I, k func(*T, []cell.I) Op) Op {
+	if len(l) == 0 || len(r) == 0 {
+		return k(t, append(append(list.Array(list.Reverse(out)), l...), r...))
+	}
+
+	// To keep the sort stable, only take from the right when it is less.
+	t.ReplaceOp(Action(func(t *T) Op {
+		if boolean.Value(t.PopResult()) {
+			return t.merge(less, l, r[1:], pair.Cons(r[0], out), k)
+		}
+
+		return t.merge(less, l[1:], r, pair.Cons(l[0], out), k)
+	}))
+
+	return t.call(less, list.New(r[0], l[0]))
+}
+
+func (t *T) mergeSort(less command, v []cell.I, k func(*T, []cell.I) Op) Op {
+	if len(v) < 2 { //nolint:gomnd
+		return k(t, v)
+	}
+
+	mid := len(v) / 2 //nolint:gomnd
+
+	return t.mergeSort(less, v[:mid], func(t *T, l []cell.I) Op {
+		return t.mergeSort(less, v[mid:], func(t *T, r []cell.I) Op {
+			return t.merge(less, l, r, pair.Null, k)
+		})
+	})
+}
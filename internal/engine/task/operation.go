@@ -22,6 +22,10 @@ func opString(o Op) string {
 		return funcName(a)
 	}
 
+	if _, ok := o.(*cleanup); ok {
+		return "Cleanup"
+	}
+
 	if r, ok := o.(*registers); ok {
 		s := "Restore("
 		comma := ""
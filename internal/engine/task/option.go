@@ -0,0 +1,214 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/interface/boolean"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
+	"github.com/michaelmacinnis/oh/internal/common/type/create"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
+)
+
+// Option is a keyword parameter. In a parameter list, an option is written
+// as --name or --name=default. A name that is a single letter can also be
+// written as -n or -n=default. An option without a default, or with a
+// default of true or false, is a flag.
+type Option struct {
+	Default cell.I // Default value.
+	Flag    bool   // True if the option takes no value.
+	Label   string // Option as written, without its default.
+	Name    string // Variable name.
+}
+
+// Options separates the options in the parameter labels plabels from the
+// positional parameters, which are returned as a list.
+func options(plabels cell.I) (cell.I, []*Option) {
+	params := pair.Null
+	opts := []*Option(nil)
+
+	for ; plabels != pair.Null; plabels = pair.Cdr(plabels) {
+		label := pair.Car(plabels)
+
+		s, ok := declaration(label)
+		if !ok {
+			params = list.Append(params, label)
+
+			continue
+		}
+
+		o := &Option{Default: create.Bool(false), Flag: true}
+
+		o.Label, s, ok = strings.Cut(s, "=")
+		if ok && s != "true" && s != "false" {
+			o.Default = sym.New(s)
+			o.Flag = false
+		} else if ok {
+			o.Default = create.Bool(s == "true")
+		}
+
+		o.Name = strings.TrimLeft(o.Label, "-")
+		if o.Name == "" || o.Name == "help" || o.Label[1] != '-' && len(o.Name) != 1 {
+			panic(exception.New(exception.Error, "invalid option '"+o.Label+"'"))
+		}
+
+		opts = append(opts, o)
+	}
+
+	return params, opts
+}
+
+// Keywords defines a variable in e for each option and returns the
+// arguments in args that are not options. It returns true if usage
+// information was requested with --help.
+func (l *Labels) keywords(e scope.I, args cell.I) (cell.I, bool) {
+	values := map[string]cell.I{}
+
+	find := func(name string, short bool) *Option {
+		for _, o := range l.Options {
+			if o.Name == name && (!short || len(name) == 1) {
+				return o
+			}
+		}
+
+		prefix := "--"
+		if short {
+			prefix = "-"
+		}
+
+		panic(exception.New(exception.Error, "unknown option '"+prefix+name+"'; usage: "+l.usage()))
+	}
+
+	value := func(o *Option, v string, ok bool) {
+		switch {
+		case o.Flag && !ok:
+			values[o.Name] = create.Bool(true)
+		case o.Flag && (v == "true" || v == "false"):
+			values[o.Name] = create.Bool(v == "true")
+		case o.Flag:
+			panic(exception.New(exception.Error, "option '"+o.Label+"' expects true or false"))
+		case ok:
+			values[o.Name] = sym.New(v)
+		case args == pair.Null:
+			panic(exception.New(exception.Error, "option '"+o.Label+"' requires a value"))
+		default:
+			values[o.Name] = pair.Car(args)
+			args = pair.Cdr(args)
+		}
+	}
+
+	positional := []cell.I{}
+
+	for args != pair.Null {
+		arg := pair.Car(args)
+		args = pair.Cdr(args)
+
+		// Only words are options. A quoted string never is.
+		s := ""
+		if sym.Is(arg) {
+			s = common.String(arg)
+		}
+
+		switch {
+		case s == "--":
+			positional = append(positional, list.Array(args)...)
+			args = pair.Null
+
+		case s == "--help":
+			return nil, true
+
+		case strings.HasPrefix(s, "--"):
+			name, v, ok := strings.Cut(s[2:], "=")
+			value(find(name, false), v, ok)
+
+		case len(s) > 1 && s[0] == '-' && !strings.ContainsRune(".0123456789", rune(s[1])):
+			// Single letter options can be combined, -abc. An
+			// option that takes a value ends the combination.
+			for i := 1; i < len(s); i++ {
+				o := find(s[i:i+1], true)
+
+				v := strings.TrimPrefix(s[i+1:], "=")
+				if o.Flag || v == "" {
+					value(o, "", false)
+
+					continue
+				}
+
+				value(o, v, true)
+
+				break
+			}
+
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	for _, o := range l.Options {
+		v, ok := values[o.Name]
+		if !ok {
+			v = o.Default
+		}
+
+		policy.Variable(o.Name)
+		e.Define(o.Name, v)
+	}
+
+	return list.New(positional...), false
+}
+
+// Usage returns a synopsis of the options and parameters of a routine.
+func (l *Labels) usage() string {
+	words := []string{}
+
+	for _, o := range l.Options {
+		w := o.Label
+		if !o.Flag || boolean.Value(o.Default) {
+			w += "=" + common.String(o.Default)
+		}
+
+		words = append(words, "["+w+"]")
+	}
+
+	for params := l.Params; params != pair.Null; params = pair.Cdr(params) {
+		label := pair.Car(params)
+		if pair.Is(label) {
+			words = append(words, "["+common.String(pair.Car(label))+" ...]")
+		} else {
+			words = append(words, common.String(label))
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Declaration returns the text of label if it declares an option.
+func declaration(label cell.I) (string, bool) {
+	s := ""
+
+	switch {
+	case sym.Is(label) || str.Is(label):
+		s = common.String(label)
+
+	case label != pair.Null && pair.Is(label) && common.String(pair.Car(label)) == "mend":
+		// An option with a default, --name=value, is parsed as a
+		// word joined from the parts on either side of the '='.
+		for _, c := range list.Array(pair.Cddr(label)) {
+			if !sym.Is(c) && !str.Is(c) {
+				return "", false
+			}
+
+			s += common.String(c)
+		}
+	}
+
+	return s, strings.HasPrefix(s, "-") && len(s) > 1
+}
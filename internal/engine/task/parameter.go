@@ -0,0 +1,202 @@
+// Released under an MIT license. See LICENSE.
+
+package task
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"github.com/michaelmacinnis/oh/internal/common/expansion"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/num"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/validate"
+	"github.com/rivo/uniseg"
+)
+
+// The characters allowed in a name written as $name in a string. Names
+// with other characters can be written as ${name}.
+//
+//nolint:gochecknoglobals
+var bare = regexp.MustCompile(`^[!%*+\-0-9?@A-Z\[\]^_a-z]+`)
+
+// parameter evaluates a parameter expansion, ${...}, that is more than a
+// plain reference. The parser passes the text between the braces.
+func parameter(t *T) Op {
+	v := validate.Fixed(t.code, 1, 1)
+
+	s := scope.To(bound(t.Result()).self)
+
+	return t.Return(t.substitute(s, common.String(v[0])))
+}
+
+// Interpolated replaces each reference in text with the string value of
+// what it refers to. A "$$" is replaced with a single "$". A "${...}" that
+// is not a valid parameter expansion is not a reference.
+func (t *T) interpolated(s scope.I, text string) string {
+	var b strings.Builder
+
+	for {
+		i := strings.IndexByte(text, '$')
+		if i < 0 {
+			b.WriteString(text)
+
+			return b.String()
+		}
+
+		b.WriteString(text[:i])
+		text = text[i:]
+
+		switch {
+		case strings.HasPrefix(text, "$$"):
+			b.WriteByte('$')
+
+			text = text[2:]
+
+		case strings.HasPrefix(text, "${"):
+			j := braced(text)
+			if j < 0 {
+				b.WriteString(text)
+
+				return b.String()
+			}
+
+			// Text that is not a valid expansion, like "${}", is
+			// left as is.
+			if _, err := expansion.Parse(text[2:j]); err != nil {
+				b.WriteString(text[:j+1])
+			} else {
+				b.WriteString(common.String(t.substitute(s, text[2:j])))
+			}
+
+			text = text[j+1:]
+
+		default:
+			name := bare.FindString(text[1:])
+
+			if name == "" {
+				b.WriteByte('$')
+			} else {
+				b.WriteString(common.String(t.resolve(s, name)))
+			}
+
+			text = text[1+len(name):]
+		}
+	}
+}
+
+// Substitute returns the value of the parameter expansion text. Strings
+// are never split. The pattern operators are applied to each element of
+// a list and produce a list.
+func (t *T) substitute(s scope.I, text string) cell.I {
+	e, err := expansion.Parse(text)
+	if err != nil {
+		panic(exception.New(exception.Syntax, "invalid parameter expansion '${"+text+"}': "+err.Error()))
+	}
+
+	c := t.value(s, e.Name)
+	if c != nil && e.Indexed {
+		c = element(e.Name, c, t.interpolated(s, e.Index))
+	}
+
+	switch e.Op {
+	case ":-":
+		if empty(c) {
+			return str.New(t.interpolated(s, e.Word))
+		}
+
+		return c
+
+	case ":?":
+		if empty(c) {
+			msg := t.interpolated(s, e.Word)
+			if msg == "" {
+				msg = "parameter not set or empty"
+			}
+
+			panic(exception.New(exception.Error, e.Name+": "+msg))
+		}
+
+		return c
+	}
+
+	if c == nil {
+		if e.Indexed {
+			panic(exception.New(exception.Error, "'"+e.Name+"' has no element "+e.Index))
+		}
+
+		panic(exception.New(exception.NotDefined, "'"+e.Name+"' not defined"))
+	}
+
+	if e.Length {
+		if pair.Is(c) {
+			return num.Int(int(list.Length(c)))
+		}
+
+		return num.Int(uniseg.GraphemeClusterCount(common.String(c)))
+	}
+
+	if e.Op == "" {
+		return c
+	}
+
+	pattern := t.interpolated(s, e.Word)
+	with := t.interpolated(s, e.With)
+
+	if !pair.Is(c) {
+		return str.New(e.Transform(common.String(c), pattern, with))
+	}
+
+	l := pair.Null
+	for _, v := range list.Array(c) {
+		l = list.Append(l, str.New(e.Transform(common.String(v), pattern, with)))
+	}
+
+	return l
+}
+
+// Braced returns the index of the brace that closes the "${" at the start
+// of text or -1.
+func braced(text string) int {
+	depth := 0
+
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Element returns the element of the list c at index, counting from the
+// end if index is negative, or nil if there is no such element.
+func element(name string, c cell.I, index string) cell.I {
+	if !pair.Is(c) {
+		panic(exception.New(exception.TypeError, "'"+name+"' is not a list"))
+	}
+
+	i, err := strconv.ParseInt(index, 10, 64)
+	if err != nil {
+		panic(exception.New(exception.TypeError, "'"+index+"' is not an integer index"))
+	}
+
+	return pair.Car(list.Tail(c, i, pair.Cons(nil, pair.Null)))
+}
+
+// Empty returns true if c is not set, the empty list or an empty string.
+func empty(c cell.I) bool {
+	return c == nil || c == pair.Null || !pair.Is(c) && common.String(c) == ""
+}
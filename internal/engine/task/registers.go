@@ -3,6 +3,9 @@
 package task
 
 import (
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
@@ -40,6 +43,11 @@ func (m *registers) Equal(c cell.I) bool {
 	}
 }
 
+// Format implements fmt.Formatter for the continuation m.
+func (m *registers) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, m)
+}
+
 func (m *registers) Name() string {
 	return "continuation"
 }
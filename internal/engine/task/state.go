@@ -29,6 +29,8 @@ type state struct {
 
 	result cell.I
 
+	pid int
+
 	exited   bool
 	running  bool
 	stopping bool
@@ -38,7 +40,11 @@ type state struct {
 func fresh() *state {
 	m := &sync.Mutex{}
 
-	return &state{Mutex: m, resume: sync.NewCond(m), stopped: sync.NewCond(m)}
+	return &state{
+		Mutex:   m,
+		resume:  sync.NewCond(m),
+		stopped: sync.NewCond(m),
+	}
 }
 
 func (s *state) Exit() {
@@ -55,6 +61,15 @@ func (s *state) Exited() bool {
 	return s.exited
 }
 
+// Launched records the process ID of the most recent external process
+// started by the task.
+func (s *state) Launched(pid int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.pid = pid
+}
+
 // Notify notifies a waiting task that it can continue. If running is set,
 // the condition variable resume is used to signal the task to resume.
 // Calling this on a task that is not waiting results in a panic.
@@ -63,7 +78,6 @@ func (s *state) Exited() bool {
 // X X 0    X X 0 panic
 // 0 X 1    0 X 0
 // 1 X 1    1 X 0 resume signal.
-//
 func (s *state) Notify(r cell.I) {
 	s.Lock()
 	defer s.Unlock()
@@ -80,6 +94,15 @@ func (s *state) Notify(r cell.I) {
 	}
 }
 
+// PID returns the process ID of the most recent external process started by
+// the task or zero if the task has not started an external process.
+func (s *state) PID() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.pid
+}
+
 // Runnable returns true if a task is running and not stopping or waiting.
 // If the task is waiting and not stopping Runnable blocks until signaled via
 // the resume condition variable.
@@ -91,7 +114,6 @@ func (s *state) Notify(r cell.I) {
 // 1 0 1    1 0 0 ...or resumed
 // 1 1 0    0 1 0 returns false
 // 1 1 1    0 1 1 .
-//
 func (s *state) Runnable() bool {
 	s.Lock()
 	defer s.Unlock()
@@ -116,7 +138,6 @@ func (s *state) Runnable() bool {
 // 0 0 X -> 1 0 X
 // 0 1 X -> 0 0 X
 // 1 X X    1 X X panic.
-//
 func (s *state) Started() {
 	s.Lock()
 	defer s.Unlock()
@@ -138,7 +159,6 @@ func (s *state) Started() {
 // 0 X X -> 0 X X
 // 1 0 0 -> 0 0 0 waits for stopped signal
 // 1 0 1 -> 0 0 1 resumes tasks, waits for stopped signal.
-//
 func (s *state) Stop(f func()) {
 	s.Lock()
 	defer s.Unlock()
@@ -173,7 +193,6 @@ func (s *state) Stop(f func()) {
 // R S W -> R S W
 // X 0 X -> 0 0 X
 // 0 1 X -> 0 0 X signals task has stopped.
-//
 func (s *state) Stopped() {
 	s.Lock()
 	defer s.Unlock()
@@ -204,7 +223,6 @@ func (s *state) Value() cell.I {
 // R S W -> R S W
 // X X 0 -> X X 1
 // X X 1 -> X X 1 panic.
-//
 func (s *state) Wait() {
 	s.Lock()
 	defer s.Unlock()
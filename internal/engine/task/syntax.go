@@ -3,6 +3,9 @@
 package task
 
 import (
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/format"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 )
 
@@ -18,6 +21,11 @@ func (a *Syntax) Equal(c cell.I) bool {
 	return ok && p == a
 }
 
+// Format implements fmt.Formatter for the syntax a.
+func (a *Syntax) Format(f fmt.State, verb rune) {
+	format.Cell(f, verb, a)
+}
+
 // Name returns the name of the syntax type.
 func (a *Syntax) Name() string {
 	return "syntax"
@@ -7,26 +7,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/michaelmacinnis/adapted"
 	"github.com/michaelmacinnis/oh/internal/common"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/integer"
 	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
 	"github.com/michaelmacinnis/oh/internal/common/interface/reference"
 	"github.com/michaelmacinnis/oh/internal/common/interface/scope"
 	"github.com/michaelmacinnis/oh/internal/common/struct/frame"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/pipe"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
+	"github.com/michaelmacinnis/oh/internal/system/process"
 )
 
 const debug = false
 
 type monitor interface {
 	Await(fn func(), t *T, ts ...*T)
-	Execute(t *T, path string, argv []string, attr *os.ProcAttr) error
+	Execute(t *T, path string, argv []string, attr *os.ProcAttr, sb *process.Sandbox) error
 	Spawn(p, c *T, fn func())
 	Stopped(t *T)
 }
@@ -66,6 +72,8 @@ func (t *T) CellValue(k string) cell.I {
 
 // Chdir changes the working directory by modifying PWD and OLDPWD in the current scope.
 func (t *T) Chdir(s string) cell.I {
+	policy.Variable("PWD")
+
 	rv := sym.True
 
 	_, r := t.frame.Resolve("PWD")
@@ -103,6 +111,8 @@ func (t *T) Closure() *Closure {
 
 	// TODO: Check plabels is a list of symbols. Last element can be a list.
 
+	plabels, opts := options(plabels)
+
 	first := pair.Car(t.code)
 
 	elabel := pair.Null
@@ -114,9 +124,10 @@ func (t *T) Closure() *Closure {
 	return &Closure{
 		Body: t.code,
 		Labels: Labels{
-			Env:    elabel,
-			Params: plabels,
-			Self:   slabel,
+			Env:     elabel,
+			Options: opts,
+			Params:  plabels,
+			Self:    slabel,
 		},
 		Op:    Action(apply),
 		Scope: t.frame.Scope(),
@@ -178,8 +189,7 @@ func (t *T) Step(s Op) (op Op) {
 			return
 		}
 
-		errmsg := fmt.Sprintf("%v", r)
-		t.code = list.New(sym.New("throw"), str.New(errmsg))
+		t.code = list.New(sym.New("throw"), t.raised(r))
 
 		op = t.PushOp(Action(EvalCommand))
 	}()
@@ -213,6 +223,8 @@ func (t *T) Step(s Op) (op Op) {
 		println("")
 	}
 
+	policy.Memory()
+
 	op = s.Perform(t)
 
 	return op
@@ -223,6 +235,21 @@ func (t *T) Stop() {
 	t.state.Stop(nil)
 }
 
+// call applies the command c to args. Unlike a command in code, the
+// arguments have already been evaluated and are passed as they are.
+func (t *T) call(c command, args cell.I) Op {
+	b, ok := c.(*binding)
+	if !ok {
+		b = bind(c, t.frame.Scope())
+	}
+
+	t.PushResult(b)
+
+	t.code = args
+
+	return t.PushOp(b.Closure().Op)
+}
+
 func (t *T) expand(args cell.I) cell.I {
 	l := pair.Null
 
@@ -254,7 +281,7 @@ func (t *T) expand(args cell.I) cell.I {
 
 		m, err := adapted.Glob(path)
 		if err != nil || len(m) == 0 {
-			panic("no matches found: " + s)
+			panic(exception.New(exception.IO, "no matches found: "+s))
 		}
 
 		for _, v := range m {
@@ -272,15 +299,74 @@ func (t *T) expand(args cell.I) cell.I {
 	return l
 }
 
+// files returns the files inherited by an external process. The first three
+// are taken from stdin, stdout and stderr. Other descriptors are listed, most
+// recently redirected first, in _descriptors_ and taken from fd3, fd4, ....
+func (t *T) files() []*os.File {
+	files := []*os.File{
+		file(t.value(nil, "stdin"), "r"),
+		file(t.value(nil, "stdout"), "w"),
+		file(t.value(nil, "stderr"), "w"),
+	}
+
+	seen := map[int]bool{}
+
+	l := t.value(nil, "_descriptors_")
+	for ; l != nil && l != pair.Null; l = pair.Cdr(l) {
+		d := pair.Car(l)
+
+		n := int(integer.Value(pair.Car(d)))
+		if n < 3 || seen[n] {
+			continue
+		}
+
+		seen[n] = true
+
+		for len(files) <= n {
+			files = append(files, nil)
+		}
+
+		files[n] = file(t.value(nil, "fd"+strconv.Itoa(n)), common.String(pair.Cadr(d)))
+	}
+
+	return files
+}
+
+// Raised returns the exception for the value passed to panic. Values other
+// than exceptions are converted and the location and trace of the current
+// frame are recorded.
+func (t *T) raised(r interface{}) *exception.T {
+	var e *exception.T
+
+	switch r := r.(type) {
+	case *exception.T:
+		e = r
+	case *policy.Violation:
+		e = exception.Wrap(exception.Restricted, r)
+	case error:
+		e = exception.Wrap(exception.Error, r)
+	default:
+		e = exception.New(exception.Error, fmt.Sprintf("%v", r))
+	}
+
+	return e.Raised(t.frame.Loc(), t.backtrace(true))
+}
+
 func (t *T) resolve(s scope.I, k string) cell.I {
 	v := t.value(s, k)
 	if v == nil {
-		panic("'" + k + "' not defined")
+		panic(exception.New(exception.NotDefined, "'"+k+"' not defined"))
 	}
 
 	return v
 }
 
+func (t *T) sandbox() *process.Sandbox {
+	sb, _ := t.value(nil, "_sandbox_").(*process.Sandbox)
+
+	return sb
+}
+
 func (t *T) stringValue(k string) string {
 	v := t.value(nil, k)
 	if v == nil {
@@ -298,6 +384,18 @@ func (t *T) tildeExpand(s string) string {
 	return filepath.Join(t.stringValue("HOME"), s[1:])
 }
 
+func file(c cell.I, mode string) *os.File {
+	if c == nil || c == pair.Null {
+		return nil
+	}
+
+	if mode == "r" {
+		return pipe.R(c)
+	}
+
+	return pipe.W(c)
+}
+
 func (t *T) value(s scope.I, k string) cell.I {
 	var r reference.I
 
@@ -0,0 +1,325 @@
+// Released under an MIT license. See LICENSE.
+
+package reader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/struct/loc"
+	"github.com/michaelmacinnis/oh/internal/common/struct/token"
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+)
+
+// The binary form of a parsed file is:
+//
+//	magic version grammar digest(text) strings commands
+//
+// where strings is a count followed by that many length-prefixed strings
+// and commands is a count followed by that many cells. Each cell is a tag
+// followed by its contents. Strings and counts are uvarints and strings
+// are referenced by their index.
+//
+// Lists are encoded as the number of elements, the elements and the final
+// tail so that long lists do not require deep recursion. Symbols with a
+// location are encoded with their line, character and text. The name of
+// the file is not encoded so that the same text parsed from different
+// files can share an encoding.
+const (
+	magic   = "ohc"
+	version = 1
+)
+
+const (
+	tagNil byte = iota
+	tagNull
+	tagList
+	tagSym
+	tagPlus
+	tagStr
+	tagLiteral
+)
+
+// ErrStale is returned when decoding a binary form that was not encoded
+// from the given text or was encoded by a different version of the grammar.
+var ErrStale = errors.New("encoded for different text")
+
+// Encode returns the binary form of the commands cs parsed from text.
+func Encode(text string, cs []cell.I) ([]byte, error) {
+	e := &encoder{index: map[string]uint64{}}
+
+	e.uvarint(uint64(len(cs)))
+
+	for _, c := range cs {
+		err := e.cell(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+
+	sum := digest(text)
+
+	b.WriteString(magic)
+	b.WriteByte(version)
+	b.WriteByte(grammar)
+	b.Write(sum[:])
+
+	b.Write(binary.AppendUvarint(nil, uint64(len(e.strings))))
+
+	for _, s := range e.strings {
+		b.Write(binary.AppendUvarint(nil, uint64(len(s))))
+		b.WriteString(s)
+	}
+
+	b.Write(e.body.Bytes())
+
+	return b.Bytes(), nil
+}
+
+// Decode returns the commands in the binary form b of text. Locations are
+// labelled with name.
+func Decode(name, text string, b []byte) (cs []cell.I, err error) {
+	header := len(magic) + 2 + sha256.Size
+	if len(b) < header || string(b[:len(magic)]) != magic || b[len(magic)] != version {
+		return nil, errors.New("not an encoded oh file")
+	}
+
+	sum := digest(text)
+	if b[len(magic)+1] != grammar || !bytes.Equal(sum[:], b[len(magic)+2:header]) {
+		return nil, ErrStale
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		cs = nil
+		err = fmt.Errorf("corrupt encoded oh file: %v", r)
+	}()
+
+	d := &decoder{b: b[header:], name: name}
+
+	d.strings = make([]string, d.count())
+
+	for i := range d.strings {
+		d.strings[i] = string(d.bytes(d.uvarint()))
+	}
+
+	cs = make([]cell.I, d.count())
+
+	for i := range cs {
+		cs[i] = d.cell()
+	}
+
+	return cs, nil
+}
+
+type encoder struct {
+	body    bytes.Buffer
+	index   map[string]uint64
+	strings []string
+}
+
+func (e *encoder) cell(c cell.I) error {
+	switch v := c.(type) {
+	case *sym.Plus:
+		l := v.Source()
+
+		e.tag(tagPlus)
+		e.string(v.String())
+		e.uvarint(uint64(l.Line))
+		e.uvarint(uint64(l.Char))
+		e.string(l.Text)
+
+		return nil
+	case *sym.T:
+		e.tag(tagSym)
+		e.string(v.String())
+
+		return nil
+	case *str.T:
+		e.tag(tagStr)
+		e.string(v.String())
+
+		return nil
+	}
+
+	if c == nil {
+		// An empty block is parsed as nil.
+		e.tag(tagNil)
+
+		return nil
+	}
+
+	if c == pair.Null {
+		e.tag(tagNull)
+
+		return nil
+	}
+
+	if pair.Is(c) {
+		elements := []cell.I{}
+
+		for ; pair.Is(c) && c != pair.Null; c = pair.Cdr(c) {
+			elements = append(elements, pair.Car(c))
+		}
+
+		e.tag(tagList)
+		e.uvarint(uint64(len(elements)))
+
+		for _, element := range elements {
+			err := e.cell(element)
+			if err != nil {
+				return err
+			}
+		}
+
+		return e.cell(c)
+	}
+
+	if l, ok := c.(literal.I); ok {
+		e.tag(tagLiteral)
+		e.string(l.Literal())
+
+		return nil
+	}
+
+	return fmt.Errorf("cannot encode %s", c.Name())
+}
+
+func (e *encoder) string(s string) {
+	i, ok := e.index[s]
+	if !ok {
+		i = uint64(len(e.strings))
+		e.index[s] = i
+		e.strings = append(e.strings, s)
+	}
+
+	e.uvarint(i)
+}
+
+func (e *encoder) tag(t byte) {
+	e.body.WriteByte(t)
+}
+
+func (e *encoder) uvarint(n uint64) {
+	e.body.Write(binary.AppendUvarint(nil, n))
+}
+
+type decoder struct {
+	b       []byte
+	name    string
+	strings []string
+}
+
+func (d *decoder) bytes(n uint64) []byte {
+	if n > uint64(len(d.b)) {
+		panic("unexpected end of input")
+	}
+
+	v := d.b[:n]
+	d.b = d.b[n:]
+
+	return v
+}
+
+func (d *decoder) cell() cell.I {
+	switch t := d.bytes(1)[0]; t {
+	case tagNil:
+		return nil
+
+	case tagNull:
+		return pair.Null
+
+	case tagList:
+		elements := make([]cell.I, d.count())
+		for i := range elements {
+			elements[i] = d.cell()
+		}
+
+		c := d.cell()
+		for i := len(elements) - 1; i >= 0; i-- {
+			c = pair.Cons(elements[i], c)
+		}
+
+		return c
+
+	case tagSym:
+		return sym.New(d.string())
+
+	case tagPlus:
+		v := d.string()
+
+		l := &loc.T{Line: int(d.uvarint()), Char: int(d.uvarint()), Name: d.name}
+		l.Text = d.string()
+
+		return sym.Token(token.New(token.Symbol, v, l))
+
+	case tagStr:
+		return str.New(d.string())
+
+	case tagLiteral:
+		return parseLiteral(d.string())
+
+	default:
+		panic(fmt.Sprintf("unknown tag %d", t))
+	}
+}
+
+// Every counted item takes at least one byte.
+func (d *decoder) count() uint64 {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		panic("count out of range")
+	}
+
+	return n
+}
+
+func (d *decoder) string() string {
+	i := d.uvarint()
+	if i >= uint64(len(d.strings)) {
+		panic("string index out of range")
+	}
+
+	return d.strings[i]
+}
+
+func (d *decoder) uvarint() uint64 {
+	n, w := binary.Uvarint(d.b)
+	if w <= 0 {
+		panic("invalid uvarint")
+	}
+
+	d.b = d.b[w:]
+
+	return n
+}
+
+// Values that are not lists, strings or symbols only appear in parsed code
+// as meta literals and are encoded as such.
+func parseLiteral(s string) cell.I {
+	c, err := New("literal").Scan(s + "\n")
+	if err != nil {
+		panic(exception.Wrap(exception.Syntax, err))
+	}
+
+	if c == nil {
+		panic("incomplete literal " + s)
+	}
+
+	// The reader returns a list containing the command.
+	return pair.Car(c)
+}
@@ -0,0 +1,155 @@
+// Released under an MIT license. See LICENSE.
+
+package reader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/common/struct/loc"
+	"github.com/michaelmacinnis/oh/internal/common/type/pair"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/engine/boot"
+)
+
+const text = `define f: method (a) {
+    echo "a is ${a}" 'single' $'tab\t' (|map a 1|) (|duration 1s|)
+}
+define g: method () {}
+f (|cons 1 2|) 2>&1 | grep -v x > /dev/null
+`
+
+func TestBootCompiled(t *testing.T) {
+	_, err := Decode("boot.oh", boot.Script(), boot.Compiled())
+	if err != nil {
+		t.Fatalf("boot.ohc is out of date (run go generate): %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	b := encode(t, text)
+
+	_, err := Decode("test", text+"\n", b)
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("expected stale error, got %v", err)
+	}
+
+	other := append([]byte{}, b...)
+	other[len(magic)+1]++
+
+	_, err = Decode("test", text, other)
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("expected stale error for another grammar, got %v", err)
+	}
+
+	_, err = Decode("test", text, b[:len(b)-1])
+	if err == nil {
+		t.Fatal("expected error decoding truncated input")
+	}
+
+	_, err = Decode("test", text, []byte("not encoded"))
+	if err == nil {
+		t.Fatal("expected error decoding invalid input")
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("OH_CACHE", dir)
+
+	cs, err := Parse("first", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "parsed"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %v (%v)", entries, err)
+	}
+
+	cached, err := Parse("second", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same(t, cs, cached, "second")
+}
+
+func TestRoundTrip(t *testing.T) {
+	cs, err := Commands("test", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := Decode("test", text, encode(t, text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same(t, cs, decoded, "test")
+}
+
+func encode(t *testing.T, text string) []byte {
+	t.Helper()
+
+	cs, err := Commands("test", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Encode(text, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// Same checks that the commands in a and b have the same literal form and
+// that their symbols have the same locations, except for the name.
+func same(t *testing.T, a, b []cell.I, name string) {
+	t.Helper()
+
+	if len(a) != len(b) {
+		t.Fatalf("expected %d commands, got %d", len(a), len(b))
+	}
+
+	for i := range a {
+		if literal.String(a[i]) != literal.String(b[i]) {
+			t.Fatalf("expected %s, got %s", literal.String(a[i]), literal.String(b[i]))
+		}
+
+		la, lb := locations(a[i]), locations(b[i])
+		if len(la) != len(lb) || len(la) == 0 {
+			t.Fatalf("expected %d locations, got %d", len(la), len(lb))
+		}
+
+		for j := range la {
+			if lb[j].Name != name {
+				t.Fatalf("expected location name %s, got %s", name, lb[j].Name)
+			}
+
+			if la[j].Line != lb[j].Line || la[j].Char != lb[j].Char || la[j].Text != lb[j].Text {
+				t.Fatalf("expected location %v, got %v", *la[j], *lb[j])
+			}
+		}
+	}
+}
+
+func locations(c cell.I) []*loc.T {
+	if p, ok := c.(*sym.Plus); ok {
+		return []*loc.T{p.Source()}
+	}
+
+	l := []*loc.T{}
+
+	for ; c != nil && pair.Is(c) && c != pair.Null; c = pair.Cdr(c) {
+		l = append(l, locations(pair.Car(c))...)
+	}
+
+	return l
+}
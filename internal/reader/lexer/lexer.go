@@ -29,11 +29,10 @@ type T struct {
 
 	source loc.T
 
-	tokens chan *token.T
+	tokens  []*token.T // Scanned tokens waiting to be returned.
+	waiting bool       // True if more text is needed to continue scanning.
 }
 
-const qsize = 2
-
 // New creates a new lexer/scanner. Label can be a file name or other identifier.
 func New(label string) *T {
 	l := &T{
@@ -49,14 +48,13 @@ func New(label string) *T {
 	return l
 }
 
-// Copy makes a copy of the lexer with its own token channel.
+// Copy makes a copy of the lexer with its own token queue.
 // A copy is useful for doing partial parses for command completion.
 func (l *T) Copy() *T {
 	c := *l
 
-	copy(c.queue, l.queue)
-
-	c.tokens = make(chan *token.T, qsize)
+	c.queue = append([]string(nil), l.queue...)
+	c.tokens = append([]*token.T(nil), l.tokens...)
 
 	return &c
 }
@@ -81,23 +79,27 @@ func (l *T) Text() string {
 // Token returns the next scanned token, or nil if no token is available.
 func (l *T) Token() *token.T {
 	for {
+		if len(l.tokens) > 0 {
+			t := l.tokens[0]
+			l.tokens = l.tokens[1:]
+
+			return t
+		}
+
 		l.gather()
 
-		if len(l.bytes) == 0 {
+		if len(l.bytes) == 0 || l.waiting {
 			return nil
 		}
 
-		select {
-		case t := <-l.tokens:
-			return t
-		default:
-			state := l.state(l)
-			if state != nil {
-				l.state = state
-			} else {
-				close(l.tokens)
-			}
+		state := l.state(l)
+		if state == nil {
+			l.waiting = true
+
+			return nil
 		}
+
+		l.state = state
 	}
 }
 
@@ -130,7 +132,7 @@ func (l *T) emit(c token.Class, v string) {
 
 	t := token.New(c, v, &source)
 
-	l.tokens <- t
+	l.tokens = append(l.tokens, t)
 	l.skip()
 }
 
@@ -157,10 +159,10 @@ func (l *T) gather() {
 	}
 
 	l.queue = nil
+	l.waiting = false
 	l.bytes = bytes
 	l.index -= l.first
 	l.first = 0
-	l.tokens = make(chan *token.T, qsize)
 }
 
 func (l *T) next() token.Class {
@@ -214,6 +216,30 @@ func afterAmpersand(l *T) action {
 	}
 }
 
+func afterDescriptor(l *T) action {
+	r, w := l.peek()
+
+	l.expected = []string{" ", "& "}
+	if l.Text() == ">" {
+		l.expected = append(l.expected, "> ", "| ")
+	}
+
+	switch r {
+	case eof:
+		return nil
+	case '&':
+		l.accept(r, w)
+	case '>', '|':
+		if l.Text() == ">" {
+			l.accept(r, w)
+		}
+	}
+
+	l.emit(token.Redirect, descriptorOperator(l.Text()))
+
+	return skipHorizontalSpace
+}
+
 func afterDollar(l *T) action {
 	r, w := l.peek()
 
@@ -231,7 +257,25 @@ func afterDollar(l *T) action {
 		l.accept(r, w)
 
 		return scanDollarSingleQuoted
-	case '\t', '\n', ' ', '"', '#', '&', '(',
+	case '(': // Special-case to recognize $((.
+		rest := l.bytes[l.index:]
+		if len(rest) < 2 { //nolint:gomnd
+			return nil
+		}
+
+		if rest[1] == '(' {
+			l.accept(r, w)
+			l.accept(r, w)
+
+			return scanArithmetic
+		}
+
+		l.emit(token.Symbol, l.Text())
+	case '{':
+		l.accept(r, w)
+
+		return scanParameter
+	case '\t', '\n', ' ', '"', '#', '&',
 		')', ';', '<', '>', '`', '|', '}':
 		l.emit(token.Symbol, l.Text())
 	default:
@@ -419,6 +463,40 @@ func escapeNextCharacter(l *T) action {
 	return l.resume()
 }
 
+func scanArithmetic(l *T) action {
+	l.expected = []string{"))"}
+
+	// The expression ends at the parenthesis that closes the first of
+	// the two opening parentheses. As scanning may resume after waiting
+	// for more text, depth is calculated from the text scanned so far.
+	text := l.Text()
+	depth := strings.Count(text, "(") - strings.Count(text, ")")
+
+	for {
+		r, w := l.peek()
+		if r == eof {
+			return nil
+		}
+
+		l.accept(r, w)
+
+		switch r {
+		case '(':
+			depth++
+
+		case ')':
+			depth--
+			if depth == 0 {
+				l.emit(token.Arithmetic, l.Text())
+
+				return collectHorizontalSpace
+			}
+
+		default: // Continue and get next character.
+		}
+	}
+}
+
 func scanDollarSingleQuoted(l *T) action {
 	for {
 		c := l.next()
@@ -461,6 +539,39 @@ func scanDoubleQuoted(l *T) action {
 	}
 }
 
+func scanParameter(l *T) action {
+	l.expected = []string{"}"}
+
+	// Like an arithmetic expansion, a parameter expansion may contain
+	// others and so ends at the brace that closes the first.
+	text := l.Text()
+	depth := strings.Count(text, "{") - strings.Count(text, "}")
+
+	for {
+		r, w := l.peek()
+		if r == eof {
+			return nil
+		}
+
+		l.accept(r, w)
+
+		switch r {
+		case '{':
+			depth++
+
+		case '}':
+			depth--
+			if depth == 0 {
+				l.emit(token.Parameter, l.Text())
+
+				return collectHorizontalSpace
+			}
+
+		default: // Continue and get next character.
+		}
+	}
+}
+
 func scanSingleQuoted(l *T) action {
 	for {
 		r := l.next()
@@ -504,11 +615,24 @@ func scanSymbol(l *T) action {
 			return nil
 
 		case '\t', '\n', ' ', '"', '#', '&', '\'', '(',
-			')', ';', '<', '>', '`', '{', '|', '}':
+			')', ';', '`', '{', '|', '}':
 			l.emit(token.Symbol, l.Text())
 
 			return collectHorizontalSpace
 
+		case '<', '>':
+			s := l.Text()
+			if !descriptor(s) {
+				l.emit(token.Symbol, s)
+
+				return collectHorizontalSpace
+			}
+
+			l.emit(token.Descriptor, s)
+			l.accept(r, w)
+
+			return afterDescriptor
+
 		case ',', '.', '/', ':', '=', '@', '~':
 			s := l.Text()
 			if len(s) > 0 {
@@ -609,27 +733,65 @@ func startState(l *T, state action, ignore string) action {
 
 // Helper functions.
 
-func initial(r token.Class) action {
-	a, ok := map[token.Class]action{
-		'"':  scanDoubleQuoted,
-		'#':  skipComment,
-		'$':  afterDollar,
-		'&':  afterAmpersand,
-		'\'': scanSingleQuoted,
-		'(':  afterOpenParen,
-		'>':  afterGreaterThan,
-		'|':  afterPipe,
-	}[r]
+// A symbol made up entirely of decimal digits immediately followed by a
+// redirection operator is a file descriptor number.
+func descriptor(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
 
-	if ok {
-		return a
+func descriptorOperator(s string) string {
+	return descriptorOperators[s]
+}
+
+func initial(r token.Class) action {
+	switch r {
+	case '"':
+		return scanDoubleQuoted
+	case '#':
+		return skipComment
+	case '$':
+		return afterDollar
+	case '&':
+		return afterAmpersand
+	case '\'':
+		return scanSingleQuoted
+	case '(':
+		return afterOpenParen
+	case '>':
+		return afterGreaterThan
+	case '|':
+		return afterPipe
 	}
 
 	return scanSymbol
 }
 
 func operator(s string) string {
-	return map[string]string{
+	return operators[s]
+}
+
+//nolint:gochecknoglobals
+var (
+	descriptorOperators = map[string]string{
+		"<":  "descriptor-input-from",
+		"<&": "descriptor-duplicate-input",
+		">":  "descriptor-output-to",
+		">&": "descriptor-duplicate-output",
+		">>": "descriptor-append-output-to",
+		">|": "descriptor-output-clobbers",
+	}
+
+	operators = map[string]string{
 		"&":   "spawn",
 		"&&":  "and",
 		"<":   "input-from",
@@ -644,5 +806,5 @@ func operator(s string) string {
 		"|<":  "-named-pipe-input-from",
 		"|>":  "-named-pipe-output-to",
 		"||":  "or",
-	}[s]
-}
+	}
+)
@@ -7,6 +7,31 @@ import (
 	"github.com/michaelmacinnis/oh/internal/common/struct/token"
 )
 
+func TestArithmetic(t *testing.T) {
+	h := setup(t, "Arithmetic")
+
+	h.scan("x$((i + (n * 2))) $(ls)\n",
+		h.symbol("x"),
+		h.other(token.Arithmetic, "$((i + (n * 2)))"),
+		h.literal(" "),
+		h.symbol("$"),
+		h.literal("("),
+		h.symbol("ls"),
+		h.literal(")"),
+		h.literal("\n"),
+		nil,
+	)
+
+	// An expression can be split across calls to Scan.
+	h.scan("$((1 +", nil)
+	h.scan(" (2))", nil)
+	h.scan(")\n",
+		h.other(token.Arithmetic, "$((1 + (2)))"),
+		h.literal("\n"),
+		nil,
+	)
+}
+
 func TestBackground(t *testing.T) {
 	h := setup(t, "Background")
 
@@ -19,6 +44,33 @@ func TestBackground(t *testing.T) {
 	)
 }
 
+func TestDescriptorRedirections(t *testing.T) {
+	h := setup(t, "DescriptorRedirections")
+
+	for _, op := range []string{
+		"<", "<&", ">", ">&", ">>", ">|",
+	} {
+		v := "1 23" + op + "4\n"
+		h.scan(v,
+			h.symbol("1"),
+			h.literal(" "),
+			h.other(token.Descriptor, "23"),
+			h.redirect(op),
+			h.symbol("4"),
+			h.literal("\n"),
+			nil,
+		)
+	}
+
+	h.scan("a3>4\n",
+		h.symbol("a3"),
+		h.literal(">"),
+		h.symbol("4"),
+		h.literal("\n"),
+		nil,
+	)
+}
+
 func TestDollarDollar(t *testing.T) {
 	h := setup(t, "DollarDollar")
 
@@ -126,6 +178,28 @@ func TestMeta(t *testing.T) {
 	)
 }
 
+func TestParameter(t *testing.T) {
+	h := setup(t, "Parameter")
+
+	h.scan("oh-${v#*:}.${l[2]:-${x}}\n",
+		h.symbol("oh-"),
+		h.other(token.Parameter, "${v#*:}"),
+		h.symbol("."),
+		h.other(token.Parameter, "${l[2]:-${x}}"),
+		h.literal("\n"),
+		nil,
+	)
+
+	// An expansion can be split across calls to Scan.
+	h.scan("${x:-${y", nil)
+	h.scan("}", nil)
+	h.scan("}\n",
+		h.other(token.Parameter, "${x:-${y}}"),
+		h.literal("\n"),
+		nil,
+	)
+}
+
 func TestTrailingDollar(t *testing.T) {
 	h := setup(t, "TrailingDollar")
 
@@ -256,6 +330,15 @@ func (h *harness) other(id token.Class, s string) *token.T {
 	return token.New(id, s, &location)
 }
 
+func (h *harness) redirect(op string) *token.T {
+	h.source.Char = h.index
+	h.index += len(op)
+
+	location := h.source
+
+	return token.New(token.Redirect, descriptorOperator(op), &location)
+}
+
 func (h *harness) scan(s string, tokens ...*token.T) {
 	h.lexer.Scan(s)
 	h.expect(tokens...)
@@ -0,0 +1,183 @@
+// Released under an MIT license. See LICENSE.
+
+package reader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/reader/lexer"
+	"github.com/michaelmacinnis/oh/internal/reader/parser"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
+)
+
+// The grammar version must be incremented whenever a change to the lexer
+// or parser changes the commands produced from some text. Text parsed by
+// an earlier version is then parsed again rather than read from the cache.
+const grammar = 4
+
+// Parse returns the commands in text. Locations are labelled with name.
+//
+// Parsed text is cached, in binary form, keyed by the grammar version and
+// the hash of the text so that the same text is only lexed and parsed
+// once. The cache is kept in the directory named by OH_CACHE or, if
+// OH_CACHE is not set, in the oh directory under the user's cache
+// directory. Setting OH_CACHE to the empty string disables the cache. The
+// cache is never used in restricted mode.
+func Parse(name, text string) ([]cell.I, error) {
+	path := cached(text)
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err == nil {
+			cs, err := Decode(name, text, b)
+			if err == nil {
+				return cs, nil
+			}
+		}
+	}
+
+	cs, err := Commands(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		// The cache is an optimization. Failing to update it is not an error.
+		_ = store(path, text, cs)
+	}
+
+	return cs, nil
+}
+
+// Check returns the diagnostics for every syntax error in text.
+func Check(name, text string) parser.Diagnostics {
+	_, err := Commands(name, text)
+
+	var ds parser.Diagnostics
+	if errors.As(err, &ds) {
+		return ds
+	}
+
+	return nil
+}
+
+// Commands lexes and parses text and returns the commands in it.
+// Locations are labelled with name. If there are syntax errors in text,
+// the error returned is the parser.Diagnostics for all of them.
+func Commands(name, text string) ([]cell.I, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	l := lexer.New(name)
+
+	cs := []cell.I{}
+	p := parser.New(func(c cell.I) {
+		cs = append(cs, c)
+	}, l.Token)
+
+	var ds parser.Diagnostics
+
+	// Text is scanned a line at a time so that the text for each location
+	// is the line it is on.
+	for _, line := range strings.SplitAfter(text, "\n") {
+		l.Scan(line)
+
+		for {
+			c, err := p.Next()
+			if err != nil {
+				ds = append(ds, err.(parser.Diagnostics)...) //nolint:errorlint
+
+				continue
+			}
+
+			if c == nil {
+				break
+			}
+
+			cs = append(cs, c)
+		}
+	}
+
+	// Anything left is an incomplete command.
+	err := p.Parse()
+	if err != nil {
+		ds = append(ds, err.(parser.Diagnostics)...) //nolint:errorlint
+	}
+
+	if len(ds) > 0 {
+		return nil, ds
+	}
+
+	return cs, nil
+}
+
+func cached(text string) string {
+	if policy.Restricted() {
+		return ""
+	}
+
+	dir, ok := os.LookupEnv("OH_CACHE")
+	if !ok {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+
+		dir = filepath.Join(base, "oh")
+	}
+
+	if dir == "" {
+		return ""
+	}
+
+	sum := digest(text)
+
+	return filepath.Join(dir, "parsed", hex.EncodeToString(sum[:]))
+}
+
+// Digest returns the hash of the grammar version and text.
+func digest(text string) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte{grammar}, text...))
+}
+
+func store(path, text string, cs []cell.I) error {
+	b, err := Encode(text, cs)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".parsed-")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
@@ -0,0 +1,246 @@
+// Released under an MIT license. See LICENSE.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/struct/token"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+)
+
+// The text of an arithmetic expansion, $(( ... )), is parsed into a tree
+// that is evaluated by the arithmetic command. Each node in the tree is a
+// number, a variable name or a list of an operator name followed by its
+// operands. Operators are named, rather than written as they appear in the
+// expression, so that the tree can be printed and parsed again.
+
+// Binary operators, grouped by precedence from lowest to highest.
+//
+//nolint:gochecknoglobals
+var binary = []map[string]string{
+	{"||": "or"},
+	{"&&": "and"},
+	{"==": "eq", "!=": "ne"},
+	{"<": "lt", "<=": "le", ">": "gt", ">=": "ge"},
+	{"+": "add", "-": "sub"},
+	{"*": "mul", "/": "div", "%": "mod"},
+}
+
+// Operators two characters long. Any other operator is one character.
+//
+//nolint:gochecknoglobals
+var pairs = []string{"!=", "&&", "<=", "==", ">=", "||"}
+
+type expression struct {
+	p *T
+	t *token.T // The arithmetic expansion token.
+
+	text string // The expression without its enclosing $(( and )).
+	next int    // Index of the next byte in text.
+
+	lexeme string // The current lexeme.
+	start  int    // Index of the current lexeme in text.
+}
+
+// Arithmetic returns the tree for the arithmetic expansion t.
+func (p *T) arithmetic(t *token.T) cell.I {
+	s := t.Value()
+
+	e := &expression{p: p, t: t, text: s[3 : len(s)-2]}
+
+	if !strings.HasSuffix(s, "))") {
+		e.start = len(e.text)
+		e.fail("expected '))' at the end of the expression", "'))'")
+	}
+
+	e.scan()
+
+	if e.lexeme == "" {
+		e.fail("expected an expression", "a number", "a name", "'('")
+	}
+
+	c := e.binary(0)
+
+	if e.lexeme != "" {
+		e.fail("unexpected '"+e.lexeme+"'", "an operator")
+	}
+
+	return c
+}
+
+// <binary> ::= <binary+1> (Operator <binary+1>)* .
+func (e *expression) binary(level int) cell.I {
+	if level == len(binary) {
+		return e.unary()
+	}
+
+	c := e.binary(level + 1)
+
+	for {
+		op, ok := binary[level][e.lexeme]
+		if !ok {
+			return c
+		}
+
+		e.scan()
+
+		c = list.New(sym.New(op), c, e.binary(level+1))
+	}
+}
+
+// Fail panics with a diagnostic for the current lexeme.
+func (e *expression) fail(message string, expected ...string) {
+	d := e.p.failure(e.t, message, "", expected...)
+
+	// Point at the lexeme, if it is on the same line as the start of
+	// the expansion.
+	prefix := e.text[:e.start]
+	if !strings.Contains(prefix, "\n") {
+		d.Start.Char += len("$((") + len(prefix)
+		d.End = d.Start
+	}
+
+	panic(d)
+}
+
+// <primary> ::= Number | Name | '(' <binary> ')' .
+func (e *expression) primary() cell.I {
+	l := e.lexeme
+
+	switch {
+	case l == "(":
+		e.scan()
+
+		c := e.binary(0)
+		if e.lexeme != ")" {
+			e.fail("expected ')'", "')'")
+		}
+
+		e.scan()
+
+		return c
+
+	case l == "":
+		e.start = len(e.text)
+		e.fail("unexpected end of expression", "a number", "a name", "'('")
+
+	case l[0] == '$' || isLetter(l[0]) || number(l):
+		e.scan()
+
+		return sym.New(strings.TrimPrefix(l, "$"))
+	}
+
+	e.fail("unexpected '"+l+"'", "a number", "a name", "'('")
+
+	return nil
+}
+
+// Scan sets lexeme to the next lexeme in the expression or, at the end of
+// the expression, to the empty string.
+func (e *expression) scan() {
+	s := e.text
+
+	i := e.next
+	for i < len(s) && strings.ContainsRune(" \t\n", rune(s[i])) {
+		i++
+	}
+
+	e.start = i
+
+	j := i
+
+	switch {
+	case j == len(s):
+	case s[j] == '$' && j+1 < len(s) && s[j+1] == '{':
+		k := strings.IndexByte(s[j:], '}')
+		if k < 0 {
+			e.fail("expected '}'", "'}'")
+		}
+
+		// Strip the braces, keep the dollar sign.
+		e.lexeme = "$" + s[j+2:j+k]
+		e.next = j + k + 1
+
+		if e.lexeme == "$" {
+			e.fail("expected a name", "a name")
+		}
+
+		return
+	case s[j] == '$' || isLetter(s[j]):
+		j++
+
+		if s[i] == '$' && (j == len(s) || !isLetter(s[j])) {
+			e.fail("expected a name", "a name")
+		}
+
+		for j < len(s) && (isLetter(s[j]) || isDigit(s[j])) {
+			j++
+		}
+	case number(s[j : j+1]):
+		for j < len(s) && number(s[j:j+1]) {
+			j++
+		}
+	default:
+		j++
+
+		for _, p := range pairs {
+			if strings.HasPrefix(s[i:], p) {
+				j = i + len(p)
+
+				break
+			}
+		}
+	}
+
+	e.lexeme = s[i:j]
+	e.next = j
+}
+
+// <unary> ::= ('!' | '-' | '+')* <primary> .
+func (e *expression) unary() cell.I {
+	switch e.lexeme {
+	case "!":
+		e.scan()
+
+		return list.New(sym.New("not"), e.unary())
+	case "-":
+		e.scan()
+
+		return list.New(sym.New("neg"), e.unary())
+	case "+":
+		e.scan()
+
+		return e.unary()
+	}
+
+	return e.primary()
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// IsLetter returns true if b can start a variable name. Names in an
+// expression are more restricted than in the rest of oh, as '-' is an
+// operator. Other names can be written as ${name}.
+func isLetter(b byte) bool {
+	return b == '_' || 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z'
+}
+
+// Number returns true if s is made up of digits and decimal points.
+func number(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '.' && !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
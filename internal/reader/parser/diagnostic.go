@@ -0,0 +1,52 @@
+// Released under an MIT license. See LICENSE.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common/struct/loc"
+)
+
+// Diagnostic describes a syntax error.
+type Diagnostic struct {
+	Start      loc.T    // Location of the token in error.
+	End        loc.T    // Location of the last token skipped to recover.
+	Expected   []string // What the parser expected instead, if known.
+	Message    string   // What went wrong.
+	Suggestion string   // How the error might be fixed, if known.
+}
+
+// Diagnostics is the list of syntax errors found while parsing.
+type Diagnostics []*Diagnostic
+
+// Error returns the location of the diagnostic, its message and, if there
+// is one, its suggestion.
+func (d *Diagnostic) Error() string {
+	s := d.Start.String() + ": " + d.Message
+	if d.Suggestion != "" {
+		s += "; " + d.Suggestion
+	}
+
+	return s
+}
+
+// Error returns each diagnostic on its own line.
+func (ds Diagnostics) Error() string {
+	s := make([]string, len(ds))
+	for i, d := range ds {
+		s[i] = d.Error()
+	}
+
+	return strings.Join(s, "\n")
+}
+
+// Unwrap returns the individual diagnostics.
+func (ds Diagnostics) Unwrap() []error {
+	errs := make([]error, len(ds))
+	for i, d := range ds {
+		errs[i] = d
+	}
+
+	return errs
+}
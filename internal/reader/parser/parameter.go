@@ -0,0 +1,48 @@
+// Released under an MIT license. See LICENSE.
+
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/michaelmacinnis/oh/internal/common/expansion"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/struct/token"
+	"github.com/michaelmacinnis/oh/internal/common/type/list"
+	"github.com/michaelmacinnis/oh/internal/common/type/str"
+	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+)
+
+// Parameter returns the command for the parameter expansion t. A plain
+// reference, ${name}, resolves name just as $name does. Other expansions
+// are checked here but, as they share their implementation with string
+// interpolation, their text is passed to the parameter command as is.
+func (p *T) parameter(t *token.T) cell.I {
+	s := t.Value()
+	text := s[2 : len(s)-1]
+
+	e, err := expansion.Parse(text)
+	if err != nil {
+		d := p.failure(t, "invalid parameter expansion: "+err.Error(), "")
+
+		// Point at the error, if it is on the same line as the start
+		// of the expansion.
+		var xe *expansion.Error
+		if errors.As(err, &xe) && !strings.Contains(text[:xe.Offset], "\n") {
+			d.Start.Char += len("${") + xe.Offset
+			d.End = d.Start
+		}
+
+		panic(d)
+	}
+
+	if e.Op == "" && !e.Indexed && !e.Length {
+		source := *t.Source()
+		source.Char += len("${")
+
+		return list.New(sym.New("resolve"), sym.Token(token.New(token.Symbol, e.Name, &source)))
+	}
+
+	return list.New(sym.New("parameter"), str.New(text))
+}
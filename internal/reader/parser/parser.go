@@ -5,32 +5,58 @@ package parser
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/michaelmacinnis/adapted"
-	"github.com/michaelmacinnis/oh/internal/common"
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
 	"github.com/michaelmacinnis/oh/internal/common/struct/token"
+	"github.com/michaelmacinnis/oh/internal/common/type/blob"
+	"github.com/michaelmacinnis/oh/internal/common/type/duration"
+	"github.com/michaelmacinnis/oh/internal/common/type/hmap"
 	"github.com/michaelmacinnis/oh/internal/common/type/list"
 	"github.com/michaelmacinnis/oh/internal/common/type/num"
 	"github.com/michaelmacinnis/oh/internal/common/type/pair"
 	"github.com/michaelmacinnis/oh/internal/common/type/status"
 	"github.com/michaelmacinnis/oh/internal/common/type/str"
 	"github.com/michaelmacinnis/oh/internal/common/type/sym"
+	"github.com/michaelmacinnis/oh/internal/common/type/timestamp"
 )
 
 // T holds the state of the parser.
+//
+// The parser is re-entrant. Tokens are buffered until they form a complete
+// command. If the parser runs out of tokens in the middle of a command it
+// stops and, when more tokens are available, parses the command again from
+// its first token.
+//
+// When the parser encounters a syntax error it records a diagnostic, skips
+// to the end of the statement in error and continues. A statement ends at a
+// newline, a semicolon or a closing brace or parenthesis.
 type T struct {
 	ahead int             // Lookahead count.
 	emit  func(cell.I)    // Function to call to emit a parsed command.
+	final bool            // True if there will be no more tokens.
 	item  func() *token.T // Function to call to get another token.
 	token *token.T        // Token lookahead.
 
+	// Tokens for the command being parsed.
+	depth   int        // Unclosed braces and parentheses.
+	next    int        // Index of the next pending token.
+	pending []*token.T // Tokens not yet part of a parsed command.
+	ready   bool       // True if the pending tokens may contain a command.
+
 	// Completion state.
 	current cell.I // The command being parsed, so far.
+
+	// Error recovery state.
+	closing     []token.Class // Closing tokens expected by enclosing expressions.
+	diagnostics Diagnostics   // Syntax errors in the command being parsed.
 }
 
+// The incomplete type is used to unwind the parser when it runs out of
+// tokens in the middle of a command.
+type incomplete struct{}
+
 // New creates a new parser.
 // It connects a producer of tokens with a consumer of cells.
 func New(emit func(cell.I), item func() *token.T) *T {
@@ -38,11 +64,14 @@ func New(emit func(cell.I), item func() *token.T) *T {
 }
 
 // Copy copies the current parser but replaces its emit and item functions.
+// The copy has its own copy of any buffered tokens.
 func (p *T) Copy(emit func(cell.I), item func() *token.T) *T {
 	c := *p
 
 	c.emit = emit
 	c.item = item
+	c.closing = append([]token.Class(nil), p.closing...)
+	c.pending = append([]*token.T(nil), p.pending...)
 
 	return &c
 }
@@ -52,35 +81,232 @@ func (p *T) Current() cell.I {
 	return p.current
 }
 
+// Next returns the next complete command or nil if the tokens available do
+// not form a complete command. Tokens for an incomplete command are kept
+// and used when Next is called again. If the command contains syntax errors
+// Next returns nil and the Diagnostics for the command.
+func (p *T) Next() (cell.I, error) {
+	p.gather()
+
+	// A command can only be complete once there is a newline that is not
+	// inside braces or parentheses.
+	if !p.ready {
+		return nil, nil
+	}
+
+	return p.parse()
+}
+
 // Parse consumes tokens and emits cells until there are no more tokens.
-func (p *T) Parse() (err error) {
+// Unlike Next, Parse assumes that there will be no more tokens and so
+// parses and emits whatever it can of an incomplete command. Commands
+// containing syntax errors are not emitted. Parse returns the Diagnostics
+// for all of these commands.
+func (p *T) Parse() error {
+	p.final = true
+
+	var ds Diagnostics
+
+	for {
+		p.gather()
+
+		c, err := p.parse()
+		if err != nil {
+			ds = append(ds, err.(Diagnostics)...) //nolint:errorlint
+
+			continue
+		}
+
+		if c == nil {
+			break
+		}
+
+		p.emit(c)
+	}
+
+	if len(ds) > 0 {
+		return ds
+	}
+
+	return nil
+}
+
+func (p *T) commit() {
+	p.pending = p.pending[p.next-p.ahead:]
+	p.ahead = 0
+	p.next = 0
+	p.token = nil
+
+	p.depth = 0
+	p.ready = false
+
+	for _, t := range p.pending {
+		p.track(t)
+	}
+}
+
+func (p *T) gather() {
+	for t := p.item(); t != nil; t = p.item() {
+		p.pending = append(p.pending, t)
+		p.track(t)
+	}
+}
+
+// Attempt calls f to parse a statement. If f encounters a syntax error,
+// attempt records a diagnostic and skips the rest of the statement. The
+// statement ends at the first of the tokens in ends that is not nested in
+// an expression that starts after the error.
+func (p *T) attempt(f func() cell.I, ends ...token.Class) (c cell.I) {
+	closing := len(p.closing)
+	start := p.next - p.ahead
+
 	defer func() {
 		r := recover()
 		if r == nil {
 			return
 		}
 
-		switch r := r.(type) {
-		case error:
-			err = r
-		case string:
-			err = common.Error(r)
-		default:
-			err = fmt.Errorf("unexpected error: %v", r) //nolint:goerr113
+		if _, ok := r.(incomplete); ok {
+			panic(r)
+		}
+
+		d := p.diagnostic(r)
+
+		p.closing = p.closing[:closing]
+
+		// Skip to the end of the statement. If the statement could
+		// not even be started, skip the token that started it.
+		at := p.next - p.ahead
+
+		i := p.skip(at, ends)
+		if i == start && i < len(p.pending) {
+			i = p.skip(start+1, ends)
 		}
+
+		d.End = d.Start
+		if i > at {
+			d.End = *p.pending[i-1].Source()
+		}
+
+		p.report(d)
+
+		p.ahead = 0
+		p.next = i
+		p.token = nil
+
+		c = pair.Null
 	}()
 
-	for t := p.peek(); t != nil; t = p.peek() {
+	return f()
+}
+
+func (p *T) diagnostic(r interface{}) *Diagnostic {
+	var s string
+
+	switch r := r.(type) {
+	case *Diagnostic:
+		return r
+	case error:
+		s = r.Error()
+	case string:
+		s = r
+	default:
+		s = fmt.Sprintf("unexpected error: %v", r)
+	}
+
+	var t *token.T
+	if p.next > 0 {
+		t = p.pending[p.next-1]
+	}
+
+	return p.failure(t, s, "")
+}
+
+func (p *T) fail(t *token.T, message, suggestion string, expected ...string) {
+	panic(p.failure(t, message, suggestion, expected...))
+}
+
+func (p *T) failure(t *token.T, message, suggestion string, expected ...string) *Diagnostic {
+	d := &Diagnostic{
+		Expected:   expected,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+
+	if t == nil && len(p.pending) > 0 {
+		// At the end of input. Report the location of the last token.
+		t = p.pending[len(p.pending)-1]
+	}
+
+	if t != nil {
+		d.Start = *t.Source()
+		d.End = d.Start
+	}
+
+	return d
+}
+
+func (p *T) parse() (c cell.I, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		if _, ok := r.(incomplete); !ok {
+			panic(r)
+		}
+
+		// Start again when there are more tokens.
+		c = nil
+
+		p.ahead = 0
+		p.closing = nil
+		p.diagnostics = nil
+		p.next = 0
+		p.ready = false
+		p.token = nil
+	}()
+
+	for {
+		if p.next == len(p.pending) && p.ahead == 0 {
+			// Nothing left to parse.
+			p.commit()
+
+			return nil, nil
+		}
+
+		t := p.peek()
 		if t.Is('\n') {
 			p.consume()
+			p.commit()
 
 			continue
 		}
 
-		p.emit(p.possibleBackground())
-	}
+		// A command must end at a newline.
+		c = p.attempt(func() cell.I {
+			c := p.possibleBackground()
+			if t := p.peek(); t != nil && !t.Is('\n') {
+				p.unexpected(t, "newline")
+			}
 
-	return nil
+			return c
+		}, '\n')
+
+		p.commit()
+
+		if len(p.diagnostics) > 0 {
+			err = p.diagnostics
+			p.diagnostics = nil
+
+			return nil, err
+		}
+
+		if c != nil {
+			return c, nil
+		}
+	}
 }
 
 func (p *T) consume() *token.T {
@@ -96,23 +322,27 @@ func (p *T) consume() *token.T {
 	return t
 }
 
-func (p *T) check(c cell.I) cell.I {
-	if c == nil {
-		t := p.peek()
+// Close expects the token c that closes the innermost enclosing expression
+// and, once it is consumed, no longer expects it. See open.
+func (p *T) close(c token.Class) {
+	p.expect(c)
 
-		loc := t.Source()
-		l := loc.Name
-		x := strconv.Itoa(loc.Char)
-		y := strconv.Itoa(loc.Line)
+	p.closing = p.closing[:len(p.closing)-1]
+}
 
-		panic(l + ":" + y + ":" + x + ": unexpected '" + t.Source().Text + "'")
+// Check panics with a diagnostic if c is nil. The diagnostic says that
+// what was expected instead of the current token.
+func (p *T) check(c cell.I, what string) cell.I {
+	if c == nil {
+		p.unexpected(p.peek(), what)
 	}
 
 	return c
 }
 
 func (p *T) expect(cs ...token.Class) {
-	if p.peek().Is(cs...) {
+	t := p.peek()
+	if t.Is(cs...) {
 		p.consume()
 
 		return
@@ -122,21 +352,25 @@ func (p *T) expect(cs ...token.Class) {
 	n := len(cs)
 	e := make([]string, n)
 
-	for i, c := range cs[:n-1] {
-		e[i] = c.String()
+	for i, c := range cs {
+		e[i] = describe(c)
 	}
 
-	l := cs[n-1].String()
+	l := e[n-1]
 	if n > 2 { //nolint:gomnd
 		l = ", or " + l
 	} else if n > 1 {
 		l = " or " + l
 	}
 
-	l = strings.Join(e, ", ") + l
-	s := p.peek().Value()
+	l = strings.Join(e[:n-1], ", ") + l
 
-	panic("expected " + l + ` got "` + s + `"`)
+	h := "add " + e[0]
+	if t != nil {
+		h += " before " + quote(t)
+	}
+
+	p.fail(t, "expected "+l+" got "+quote(t), h, e...)
 }
 
 func (p *T) peek() *token.T {
@@ -144,7 +378,16 @@ func (p *T) peek() *token.T {
 		return p.token
 	}
 
-	t := p.item()
+	if p.next == len(p.pending) {
+		if p.final {
+			return nil
+		}
+
+		panic(incomplete{})
+	}
+
+	t := p.pending[p.next]
+	p.next++
 
 	p.token = t
 	p.ahead = 1
@@ -152,6 +395,84 @@ func (p *T) peek() *token.T {
 	return t
 }
 
+func (p *T) open(c token.Class) {
+	p.closing = append(p.closing, c)
+}
+
+// Report adds the diagnostic d, unless it repeats the last diagnostic
+// added. The same error at the end of input is seen once for each level of
+// nesting.
+func (p *T) report(d *Diagnostic) {
+	if n := len(p.diagnostics); n > 0 {
+		l := p.diagnostics[n-1]
+		if l.Start == d.Start && l.Message == d.Message {
+			return
+		}
+	}
+
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+// Skip returns the index of the first pending token, at or after index i,
+// that is one of the tokens in ends and is not nested in an expression that
+// starts at or after i.
+func (p *T) skip(i int, ends []token.Class) int {
+	depth := 0
+
+	for ; i < len(p.pending); i++ {
+		t := p.pending[i]
+
+		switch {
+		case depth == 0 && t.Is(ends...):
+			return i
+		case t.Is('(', '{', token.MetaOpen, token.Substitute):
+			depth++
+		case depth > 0 && t.Is(')', '}', token.MetaClose):
+			depth--
+		}
+	}
+
+	return len(p.pending)
+}
+
+// Ends returns the tokens that can end a statement: a newline, a
+// semicolon or the closing token of any enclosing expression.
+func (p *T) ends() []token.Class {
+	return append([]token.Class{'\n', ';'}, p.closing...)
+}
+
+// Track updates the nesting depth, and whether a command may be complete,
+// for the pending token t.
+func (p *T) track(t *token.T) {
+	switch {
+	case t.Is('(', '{', token.MetaOpen):
+		p.depth++
+	case t.Is(')', '}', token.MetaClose):
+		p.depth--
+	case t.Is('\n'):
+		p.ready = p.ready || p.depth <= 0
+	}
+}
+
+// Unexpected panics with a diagnostic for the unexpected token t. If
+// known, what was expected instead of t should be passed as expected.
+func (p *T) unexpected(t *token.T, expected ...string) {
+	if t == nil {
+		p.fail(t, "unexpected end of input", "the command is incomplete", expected...)
+	}
+
+	m := "unexpected " + quote(t)
+
+	h := ""
+	if t.Is(')', '}', token.MetaClose) {
+		h = "remove the unmatched " + quote(t)
+	} else if len(expected) > 0 {
+		h = "expected " + expected[0]
+	}
+
+	p.fail(t, m, h, expected...)
+}
+
 // T state functions.
 
 // <possibleBackground> ::= <command> '&'?
@@ -218,7 +539,7 @@ func (p *T) possiblePipeline() cell.I {
 
 // <possibleSequence> ::= <possibleRedirection> (';' <possibleRedirection>)* .
 func (p *T) possibleSequence() cell.I {
-	c := p.possibleRedirection()
+	c := p.attempt(p.possibleRedirection, p.ends()...)
 
 	if p.peek().Is(';') {
 		c = list.New(sym.New("block"), c)
@@ -226,20 +547,33 @@ func (p *T) possibleSequence() cell.I {
 		for p.peek().Is(';') {
 			p.consume()
 
-			c = list.Append(c, p.possibleRedirection())
+			c = list.Append(c, p.attempt(p.possibleRedirection, p.ends()...))
 		}
 	}
 
 	return c
 }
 
-// <possibleRedirection> ::= <possibleSustitution> (Redirect <expression>)* .
+// <possibleRedirection> ::= <possibleSustitution> (Descriptor? Redirect <expression>)* .
 func (p *T) possibleRedirection() cell.I {
 	c := p.possibleSubstitution()
 
-	for p.peek().Is(token.Redirect) {
-		s := sym.Token(p.consume())
-		c = list.New(s, p.check(p.implicitJoin(p.element())), c)
+	for p.peek().Is(token.Descriptor, token.Redirect) {
+		var n cell.I
+		if p.peek().Is(token.Descriptor) {
+			n = sym.Token(p.consume())
+		}
+
+		s := sym.Token(p.peek())
+		p.consume()
+
+		e := p.check(p.implicitJoin(p.element()), "a file name")
+
+		if n == nil {
+			c = list.New(s, e, c)
+		} else {
+			c = list.New(s, n, e, c)
+		}
 
 		for p.peek().Is(token.Space) {
 			p.consume()
@@ -279,6 +613,7 @@ func (p *T) possibleSubstitution() cell.I {
 func (p *T) braces() (c cell.I) {
 	if p.peek().Is('{') {
 		p.consume()
+		p.open('}')
 
 		n := p.peek()
 
@@ -289,12 +624,12 @@ func (p *T) braces() (c cell.I) {
 			c = p.subStatement()
 		case n.Is('{'):
 			c = p.braces()
-			p.expect('}')
+			p.close('}')
 		default:
 			c = p.implicitJoin(p.element())
 			c = pair.Cons(c, pair.Null)
 
-			p.expect('}')
+			p.close('}')
 		}
 	}
 
@@ -319,11 +654,14 @@ func (p *T) assignments() (c, l cell.I) {
 			break
 		}
 
+		// A word that starts with a dash, like --name=value, is an
+		// option, not an assignment.
 		e := p.peek()
-		if sym.Is(c) && e.Is(token.Symbol) && e.Value() == "=" {
+		if sym.Is(c) && !strings.HasPrefix(sym.To(c).String(), "-") &&
+			e.Is(token.Symbol) && e.Value() == "=" {
 			p.consume()
 
-			v := p.check(p.implicitJoin(p.element()))
+			v := p.check(p.implicitJoin(p.element()), "a value")
 
 			l = list.Append(l, list.New(sym.New("export"), c, v))
 
@@ -339,8 +677,11 @@ func (p *T) assignments() (c, l cell.I) {
 }
 
 func (p *T) statement() (c cell.I) {
-	// Reset current command.
-	p.current = pair.Null
+	// Reset current command. If there are no more tokens, keep the last
+	// command for completion.
+	if p.peek() != nil {
+		p.current = pair.Null
+	}
 
 	c, l := p.assignments()
 	if l != pair.Null {
@@ -389,7 +730,7 @@ func (p *T) statement() (c cell.I) {
 func (p *T) subStatement() cell.I {
 	c := p.block()
 
-	p.expect('}')
+	p.close('}')
 
 	for p.peek().Is(token.Space) {
 		p.consume()
@@ -407,13 +748,20 @@ func (p *T) block() cell.I {
 	c := pair.Null
 
 	for !p.peek().Is('}') {
-		if p.peek().Is('\n') {
+		t := p.peek()
+		if t == nil {
+			p.fail(t, "unexpected end of input", "add '}'", "'}'")
+		}
+
+		if t.Is('\n') {
 			p.consume()
 
 			continue
 		}
 
-		c = list.Append(c, p.check(p.possibleBackground()))
+		c = list.Append(c, p.attempt(func() cell.I {
+			return p.check(p.possibleBackground(), "a command")
+		}, p.ends()...))
 	}
 
 	return c
@@ -443,7 +791,7 @@ func (p *T) element() cell.I {
 	if p.peek().Is('`') {
 		p.consume()
 
-		c := p.check(p.value())
+		c := p.check(p.value(), "a command in parentheses")
 
 		c = pair.Cons(sym.New("capture"), list.New(c))
 		c = list.New(sym.New("splice"), c)
@@ -460,30 +808,42 @@ func (p *T) expression() cell.I {
 
 		c := p.braces()
 		if c == nil {
-			c = p.check(p.expression())
+			c = p.check(p.expression(), "a name")
 		} else {
 			c = pair.Car(c)
 		}
 
-		return list.New(sym.New("resolve"), p.check(c))
+		return list.New(sym.New("resolve"), p.check(c, "a name"))
 	}
 
 	return p.value()
 }
 
-func (p *T) meta(c cell.I) cell.I {
+func (p *T) meta(o *token.T, c cell.I) cell.I {
 	t := pair.Car(c)
 
 	if !sym.Is(t) {
-		panic("meta command must start with a symbol not " + t.Name())
+		p.fail(o, "meta command must start with a symbol not "+t.Name(), "", metaCommands...)
 	}
 
 	var create func(string) cell.I = nil
 
 	switch sym.To(t).String() {
+	case "bytes":
+		create = blob.Hex
+
 	case "cons":
 		return pair.Cons(pair.Cadr(c), pair.Caddr(c))
 
+	case "duration":
+		create = duration.New
+
+	case "inexact":
+		create = num.NewInexact
+
+	case "map":
+		return hmap.New(list.Array(pair.Cdr(c))...)
+
 	case "number":
 		create = num.New
 
@@ -492,10 +852,16 @@ func (p *T) meta(c cell.I) cell.I {
 
 	case "symbol":
 		create = sym.New
+
+	case "timestamp":
+		create = timestamp.New
 	}
 
 	if create == nil {
-		panic("invalid meta command")
+		p.fail(
+			o, "invalid meta command '"+sym.To(t).String()+"'",
+			"use one of "+strings.Join(metaCommands, ", "), metaCommands...,
+		)
 	}
 
 	t = pair.Cadr(c)
@@ -521,26 +887,33 @@ func (p *T) value() cell.I {
 
 	p.consume()
 
+	closer := token.Class(')')
+	if meta {
+		closer = token.MetaClose
+	}
+
+	p.open(closer)
+
 	c := p.command()
 	if c == nil {
 		t := p.peek()
 		if t.Is(')') {
 			p.consume()
 
+			p.closing = p.closing[:len(p.closing)-1]
+
 			return pair.Null
 		}
 
-		panic("unexpected '" + t.Source().Text + "'")
+		p.unexpected(t, "a command")
 	}
 
-	if meta {
-		p.expect(token.MetaClose)
+	p.close(closer)
 
-		return p.meta(c)
+	if meta {
+		return p.meta(t, c)
 	}
 
-	p.expect(')')
-
 	return c
 }
 
@@ -556,6 +929,18 @@ func (p *T) symbol(t *token.T) cell.I {
 
 func (p *T) word() cell.I {
 	t := p.peek()
+	if t.Is(token.Arithmetic) {
+		p.consume()
+
+		return list.New(sym.New("arithmetic"), p.arithmetic(t))
+	}
+
+	if t.Is(token.Parameter) {
+		p.consume()
+
+		return p.parameter(t)
+	}
+
 	if t.Is(token.DollarSingleQuoted) {
 		p.consume()
 
@@ -563,7 +948,7 @@ func (p *T) word() cell.I {
 
 		s, err := adapted.ActualBytes(text[2 : len(text)-1])
 		if err != nil {
-			panic(err.Error())
+			p.fail(t, "invalid escape sequence in "+text, `use \\ for a backslash`)
 		}
 
 		return str.New(s)
@@ -576,7 +961,7 @@ func (p *T) word() cell.I {
 
 		s, err := adapted.ActualBytes(text[1 : len(text)-1])
 		if err != nil {
-			panic(err.Error())
+			p.fail(t, "invalid escape sequence in "+text, `use \\ for a backslash`)
 		}
 
 		return list.New(sym.New("interpolate"), str.New(s))
@@ -592,3 +977,33 @@ func (p *T) word() cell.I {
 
 	return p.symbol(t)
 }
+
+// Describe returns the text of the token class c, quoted, for diagnostics.
+func describe(c token.Class) string {
+	switch c {
+	case token.MetaClose:
+		return "'|)'"
+	case token.MetaOpen:
+		return "'(|'"
+	}
+
+	return c.String()
+}
+
+// Quote returns the text of the token t, quoted, for diagnostics.
+func quote(t *token.T) string {
+	switch {
+	case t == nil:
+		return "end of input"
+	case t.Is('\n'):
+		return "newline"
+	}
+
+	return "'" + t.Value() + "'"
+}
+
+//nolint:gochecknoglobals
+var metaCommands = []string{
+	"bytes", "cons", "duration", "inexact", "map",
+	"number", "status", "symbol", "timestamp",
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
@@ -9,6 +10,10 @@ import (
 	"github.com/michaelmacinnis/oh/internal/reader/lexer"
 )
 
+func TestBananaClipBytes(t *testing.T) {
+	check(t, "(|bytes 00ff0a|)\n")
+}
+
 func TestBananaClipCons(t *testing.T) {
 	check(t, "(|cons 1 2|)\n")
 }
@@ -17,6 +22,18 @@ func TestBananaClipConsNils(t *testing.T) {
 	check(t, "(|cons () ()|)\n")
 }
 
+func TestBananaClipDuration(t *testing.T) {
+	check(t, "(|duration 1h30m0.5s|)\n")
+}
+
+func TestBananaClipInexact(t *testing.T) {
+	check(t, "(|inexact 1.4142135623730951|)\n")
+}
+
+func TestBananaClipMap(t *testing.T) {
+	check(t, "(|map a 1 (b c) () 'd e' (|map f g|)|)\n")
+}
+
 func TestBananaClipNil(t *testing.T) {
 	check(t, "()\n")
 }
@@ -25,36 +42,78 @@ func TestBananaClipNumber(t *testing.T) {
 	check(t, "(|number 42|)\n")
 }
 
+func TestBananaClipTimestamp(t *testing.T) {
+	check(t, "(|timestamp 2024-03-01T09:00:00.25-05:00|)\n")
+}
+
 func check(t *testing.T, s string) {
+	p, _ := parse(s)
+	r, _ := parse(p)
+
+	if p != r {
+		t.Fatalf("Parsed (%s) and reparsed (%s) do not match", p, r)
+	}
+}
+
+// Parse returns the commands in s, one per line.
+func parse(s string) (string, error) {
 	l := lexer.New("test")
 
 	l.Scan(s)
 
 	p := ""
 
-	New(func(c cell.I) {
+	err := New(func(c cell.I) {
 		s := literal.String(c) + "\n"
 		p += s
 	}, l.Token).Parse()
 
-	m := lexer.New("test")
+	return p, err
+}
 
-	m.Scan(p)
+// TODO: Convert these into table-driven tests.
+// TODO: Write tests that don't involve reparsing.
 
-	r := ""
+func TestAssignment(t *testing.T) {
+	for s, expected := range map[string]string{
+		"x=1 y=2 cmd\n":        "block (export x 1) (export y 2) (cmd)",
+		"(--depth=1 (rest))\n": "((mend (|symbol $''|) --depth = 1) (rest))",
+		"(a --depth=1)\n":      "(a (mend (|symbol $''|) --depth = 1))",
+	} {
+		p, err := parse(s)
+		if err != nil || p != expected+"\n" {
+			t.Fatalf("expected %s, got %s (%v)", expected, p, err)
+		}
+	}
+}
 
-	New(func(c cell.I) {
-		s := literal.String(c) + "\n"
-		r += s
-	}, m.Token).Parse()
+func TestArithmetic(t *testing.T) {
+	check(t, "set i $((i < n && !(j + ${k-1}) * -2 % 3))\n")
 
-	if p != r {
-		t.Fatalf("Parsed (%s) and reparsed (%s) do not match", p, r)
+	expected := "echo (arithmetic (or (and (lt i 1) (ne j 2)) (le (add 1 (mul 2 3)) 4)))"
+
+	p, err := parse("echo $((i < 1 && j != 2 || 1 + 2 * 3 <= 4))\n")
+	if err != nil || p != expected+"\n" {
+		t.Fatalf("expected %s, got %s (%v)", expected, p, err)
 	}
-}
 
-// TODO: Convert these into table-driven tests.
-// TODO: Write tests that don't involve reparsing.
+	// Errors are reported at the location of the problem in the expression.
+	for s, char := range map[string]int{
+		"echo $(())\n":          9,
+		"echo $((1 + * 2))\n":   13,
+		"echo $((1 2))\n":       11,
+		"echo $((1 + (2 3)))\n": 16,
+		"echo $(($ + 1))\n":     9,
+		"echo $((${x} + $))\n":  16,
+	} {
+		_, err := parse(s)
+
+		var ds Diagnostics
+		if !errors.As(err, &ds) || len(ds) != 1 || ds[0].Start.Char != char {
+			t.Fatalf("%q: expected an error at test:1:%d, got %v", s, char, err)
+		}
+	}
+}
 
 func TestBackground(t *testing.T) {
 	check(t, "sleep 5; echo tea is ready!&\n")
@@ -64,10 +123,43 @@ func TestBoot(t *testing.T) {
 	check(t, boot.Script())
 }
 
+func TestDescriptorRedirections(t *testing.T) {
+	check(t, "sh -c 'echo >&3' 3>foo 2>&1 4<&- 5>>bar\n")
+}
+
 func TestMultipleRedirections(t *testing.T) {
 	check(t, "tr ' ' '\\n' < foo > bar\n")
 }
 
+func TestParameter(t *testing.T) {
+	check(t, "echo ${#l} ${l[-1]} ${x:-a b} ${x#*/} ${x%%.*} ${x//a/b} oh-${x}\n")
+
+	// A plain reference is the same as $name.
+	expected := "echo (resolve a-b) (parameter $'x:?unset') (mend (|symbol $''|) oh- (parameter $'x#v'))"
+
+	p, err := parse("echo ${a-b} ${x:?unset} oh-${x#v}\n")
+	if err != nil || p != expected+"\n" {
+		t.Fatalf("expected %s, got %s (%v)", expected, p, err)
+	}
+
+	// Errors are reported at the location of the problem in the expansion.
+	for s, char := range map[string]int{
+		"echo ${}\n":      8,
+		"echo ${x[]}\n":   10,
+		"echo ${#x%y}\n":  10,
+		"echo ${x:=y}\n":  9,
+		"echo ${x//}\n":   11,
+		"echo a ${l[1}\n": 13,
+	} {
+		_, err := parse(s)
+
+		var ds Diagnostics
+		if !errors.As(err, &ds) || len(ds) != 1 || ds[0].Start.Char != char {
+			t.Fatalf("%q: expected an error at test:1:%d, got %v", s, char, err)
+		}
+	}
+}
+
 func TestPipe(t *testing.T) {
 	check(t, "ls | grep .go\n")
 }
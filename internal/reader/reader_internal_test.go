@@ -0,0 +1,206 @@
+// Released under an MIT license. See LICENSE.
+
+package reader
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+	"github.com/michaelmacinnis/oh/internal/common/interface/literal"
+	"github.com/michaelmacinnis/oh/internal/engine/boot"
+	"github.com/michaelmacinnis/oh/internal/reader/parser"
+)
+
+func TestCheck(t *testing.T) {
+	text := "echo one )\n" +
+		"if true {\n" +
+		"    echo (|bogus 1|)\n" +
+		"    echo two\n" +
+		"}\n" +
+		"echo (three }\n" +
+		"define f: method () {\n" +
+		"    echo (four\n"
+
+	expected := []struct {
+		start, end string
+		expected   []string
+	}{
+		{"test:1:10", "test:1:10", nil},
+		{"test:3:10", "test:3:10", nil},
+		{"test:6:13", "test:6:13", []string{"')'"}},
+		{"test:8:15", "test:8:15", []string{"')'"}},
+		{"test:8:15", "test:8:15", []string{"'}'"}},
+	}
+
+	ds := Check("test", text)
+	if len(ds) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d:\n%v", len(expected), len(ds), ds)
+	}
+
+	for i, e := range expected {
+		d := ds[i]
+
+		if d.Start.String() != e.start || d.End.String() != e.end {
+			t.Fatalf("expected %s-%s, got %s-%s", e.start, e.end, d.Start.String(), d.End.String())
+		}
+
+		if e.expected != nil && !reflect.DeepEqual(d.Expected, e.expected) {
+			t.Fatalf("expected %q, got %q", e.expected, d.Expected)
+		}
+
+		if d.Suggestion == "" {
+			t.Fatalf("expected a suggestion for %v", d)
+		}
+	}
+
+	_, err := Commands("test", text)
+	if err == nil || err.Error() != ds.Error() {
+		t.Fatalf("expected %v, got %v", ds, err)
+	}
+
+	if Check("test", "echo ok\n") != nil {
+		t.Fatal("expected no diagnostics")
+	}
+}
+
+func TestScanError(t *testing.T) {
+	r := New("test")
+
+	_, err := r.Scan("echo (a }; echo )\n")
+	if err == nil {
+		t.Fatal("expected error scanning ')'")
+	}
+
+	// Both statements in error are reported.
+	var ds parser.Diagnostics
+	if !errors.As(err, &ds) || len(ds) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", err)
+	}
+
+	// The reader can be used after an error.
+	c, err := r.Scan("echo ok\n")
+	if err != nil || c == nil || literal.String(c) != "echo ok" {
+		t.Fatalf("expected echo ok, got %v (%v)", c, err)
+	}
+}
+
+func TestScanExpected(t *testing.T) {
+	r := New("test")
+
+	c, err := r.Scan("ls >")
+	if c != nil || err != nil {
+		t.Fatalf("expected incomplete command, got %v (%v)", c, err)
+	}
+
+	expected := []string{" ", "& ", "> ", ">& ", ">&| ", "| "}
+	if !reflect.DeepEqual(r.Expected(), expected) {
+		t.Fatalf("expected %q, got %q", expected, r.Expected())
+	}
+}
+
+func TestScanIncremental(t *testing.T) {
+	lines := []string{
+		"define f: method (a) {\n",
+		"    if $a {\n",
+		"        echo (|map k v|) \"${a}\"\n",
+		"    }\n",
+		"}\n",
+	}
+
+	r := New("test")
+
+	for i, line := range lines {
+		c, err := r.Scan(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (c == nil) != (i < len(lines)-1) {
+			t.Fatalf("unexpected result after line %d: %v", i+1, c)
+		}
+
+		if c != nil {
+			expected := literal.String(parsed(t, strings.Join(lines, ""))[0])
+			if literal.String(c) != expected {
+				t.Fatalf("expected %s, got %s", expected, literal.String(c))
+			}
+		}
+	}
+}
+
+func TestScanPartial(t *testing.T) {
+	r := New("test")
+
+	_, _ = r.Scan("if true {\n")
+
+	// A partial parse, as done for command completion, sees the whole
+	// command so far.
+	lc := r.Lexer().Copy()
+	lc.Scan("echo a ")
+
+	lp := r.Parser().Copy(func(_ cell.I) {}, lc.Token)
+
+	_ = lp.Parse()
+
+	if literal.String(lp.Current()) != "echo a" {
+		t.Fatalf("expected echo a, got %s", literal.String(lp.Current()))
+	}
+
+	// The original reader is unaffected.
+	c, err := r.Scan("}\n")
+	if err != nil || c == nil {
+		t.Fatalf("expected command, got %v (%v)", c, err)
+	}
+
+	expected := literal.String(parsed(t, "if true {\n}\n")[0])
+	if literal.String(c) != expected {
+		t.Fatalf("expected %s, got %s", expected, literal.String(c))
+	}
+}
+
+func BenchmarkScanBoot(b *testing.B) {
+	benchmarkScan(b, boot.Script())
+}
+
+func BenchmarkScanLiterals(b *testing.B) {
+	var sb strings.Builder
+
+	for i := 0; i < 10000; i++ {
+		sb.WriteString(`(|map name "item" count 42 tags (a b c) nested (|map x 1.5 y -2|)|)` + "\n")
+		sb.WriteString(`("a string with \"quotes\"" 'single' $'tab\t' (|duration 1s|))` + "\n")
+	}
+
+	benchmarkScan(b, sb.String())
+}
+
+func benchmarkScan(b *testing.B, text string) {
+	lines := strings.SplitAfter(text, "\n")
+
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r := New("bench")
+
+		for _, line := range lines {
+			_, err := r.Scan(line)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func parsed(t *testing.T, text string) []cell.I {
+	t.Helper()
+
+	cs, err := Commands("test", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cs
+}
@@ -112,7 +112,7 @@ func (j *job) Await(fn func(), p *task.T, ts ...*task.T) {
 	}
 }
 
-func (j *job) Execute(t *task.T, path string, argv []string, attr *os.ProcAttr) error {
+func (j *job) Execute(t *task.T, path string, argv []string, attr *os.ProcAttr, sb *process.Sandbox) error {
 	errq := make(chan error)
 
 	requestq <- func() {
@@ -120,11 +120,12 @@ func (j *job) Execute(t *task.T, path string, argv []string, attr *os.ProcAttr)
 
 		foregroundProcess := options.Monitor() && j == foreground
 
-		attr.Sys = process.SysProcAttr(foregroundProcess, j.group)
+		attr.Sys = process.SysProcAttr(foregroundProcess, j.group, sb)
 
 		p, err := os.StartProcess(path, argv, attr)
 		if err == nil {
 			t.Wait()
+			t.Launched(p.Pid)
 
 			if j.group == 0 {
 				j.group = p.Pid
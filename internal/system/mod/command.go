@@ -0,0 +1,212 @@
+// Released under an MIT license. See LICENSE.
+
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Usage describes the oh mod commands.
+const Usage = `Usage:
+  oh mod init                       Create oh.mod in the current directory.
+  oh mod add NAME SOURCE [VERSION]  Add and vendor a dependency.
+  oh mod remove NAME                Remove a dependency.
+  oh mod install                    Vendor all dependencies at their locked commits.
+  oh mod verify                     Check vendored files against oh.sum.
+  oh mod list                       List dependencies.
+  oh mod upgrade [NAME [VERSION]]   Resolve versions again and vendor the result.
+
+SOURCE is a git URL or a local path. VERSION is a branch, tag or commit
+and defaults to HEAD. A VERSION of - copies a local directory as is.
+`
+
+// ErrUsage is returned when oh mod is invoked incorrectly.
+var ErrUsage = errors.New(Usage)
+
+// ErrVerify is returned when vendored files do not match the lock file.
+var ErrVerify = errors.New("verification failed")
+
+// Main runs the oh mod command args for the project containing dir.
+func Main(dir string, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	cmd, args := args[0], args[1:]
+
+	if cmd == "init" {
+		if len(args) != 0 {
+			return ErrUsage
+		}
+
+		_, err := Init(dir)
+
+		return err
+	}
+
+	root, err := Find(dir)
+	if err != nil {
+		return err
+	}
+
+	p, err := Open(root)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "add":
+		return add(p, args)
+	case "install":
+		if len(args) != 0 {
+			return ErrUsage
+		}
+
+		return p.Install()
+	case "list":
+		if len(args) != 0 {
+			return ErrUsage
+		}
+
+		return list(p, w)
+	case "remove":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		return p.Remove(args[0])
+	case "upgrade":
+		return upgrade(p, args, w)
+	case "verify":
+		if len(args) != 0 {
+			return ErrUsage
+		}
+
+		return verify(p, w)
+	}
+
+	return ErrUsage
+}
+
+func add(p *project, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return ErrUsage
+	}
+
+	d := Dependency{Name: args[0], Source: args[1], Version: "HEAD"}
+	if len(args) == 3 {
+		d.Version = args[2]
+	}
+
+	err := d.check()
+	if err != nil {
+		return err
+	}
+
+	if p.Dependency(d.Name) != nil {
+		return fmt.Errorf("dependency '%s' already exists", d.Name)
+	}
+
+	_, err = p.vendor(d, true)
+	if err != nil {
+		return err
+	}
+
+	p.Dependencies = append(p.Dependencies, d)
+
+	return p.Save()
+}
+
+func list(p *project, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	for _, d := range p.Dependencies {
+		commit := "(not locked)"
+		if l, ok := p.Locked[d.Name]; ok {
+			commit = short(l.Commit)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Name, d.Version, commit, d.Source)
+	}
+
+	return tw.Flush()
+}
+
+func short(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+
+	return commit
+}
+
+func upgrade(p *project, args []string, w io.Writer) error {
+	if len(args) > 2 {
+		return ErrUsage
+	}
+
+	if len(args) == 2 {
+		d := p.Dependency(args[0])
+		if d == nil {
+			return fmt.Errorf("no dependency '%s'", args[0])
+		}
+
+		d.Version = args[1]
+	}
+
+	previous, err := p.Upgrade(args[:min(len(args), 1)]...)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(previous))
+	for name := range previous {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		was, now := previous[name], p.Locked[name]
+
+		switch {
+		case was.Commit == "":
+			fmt.Fprintf(w, "%s: %s\n", name, short(now.Commit))
+		case was.Commit == now.Commit && was.Sum == now.Sum:
+			fmt.Fprintf(w, "%s: %s (unchanged)\n", name, short(now.Commit))
+		default:
+			fmt.Fprintf(w, "%s: %s -> %s\n", name, short(was.Commit), short(now.Commit))
+		}
+	}
+
+	return nil
+}
+
+func verify(p *project, w io.Writer) error {
+	problems := p.Verify()
+
+	failed := false
+
+	for _, d := range p.Dependencies {
+		problem := problems[d.Name]
+		if problem == "" {
+			fmt.Fprintf(w, "%s: ok\n", d.Name)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "%s: %s\n", d.Name, problem)
+
+		failed = true
+	}
+
+	if failed {
+		return ErrVerify
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+// Released under an MIT license. See LICENSE.
+
+// Package mod manages the modules that an oh project depends on.
+//
+// A project is a directory with a manifest, oh.mod, that lists the
+// project's dependencies. Each dependency is vendored into the project's
+// oh_modules directory and the commit and checksum of what was vendored
+// are recorded in the project's lock file, oh.sum. When importing a module
+// oh searches the oh_modules directories of the importing file's project
+// before the directories in OHPATH.
+package mod
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files and directories that make up a project.
+const (
+	Lockfile = "oh.sum"
+	Manifest = "oh.mod"
+	Vendor   = "oh_modules"
+)
+
+// Unversioned is the version of a dependency that is copied, as is, from
+// a local directory.
+const Unversioned = "-"
+
+// Dependency is a module listed in a project's manifest.
+type Dependency struct {
+	Name    string // Directory under oh_modules.
+	Source  string // Git URL or local path. Relative paths are relative to the project.
+	Version string // Git branch, tag or commit, or "-" for a plain directory.
+}
+
+// Locked records what was vendored for a dependency.
+type Locked struct {
+	Name    string
+	Version string // The version in the manifest when the dependency was resolved.
+	Commit  string // The resolved commit, or "-" for a plain directory.
+	Sum     string // The checksum of the vendored files.
+}
+
+// T (project) is a project's manifest and lock file.
+type T struct {
+	Dependencies []Dependency
+	Locked       map[string]Locked
+
+	Root string // The directory that contains the manifest.
+}
+
+type project = T
+
+// ErrNoManifest is returned when a project cannot be found.
+var ErrNoManifest = errors.New("no " + Manifest + " found (run 'oh mod init')")
+
+// Find returns the root of the project that contains dir.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		info, err := os.Stat(filepath.Join(dir, Manifest))
+		if err == nil && !info.IsDir() {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoManifest
+		}
+
+		dir = parent
+	}
+}
+
+// Paths returns the oh_modules directories of the projects that contain
+// dir, innermost first.
+func Paths(dir string) []string {
+	paths := []string{}
+
+	if dir == "" {
+		return paths
+	}
+
+	for {
+		vendor := filepath.Join(dir, Vendor)
+
+		info, err := os.Stat(vendor)
+		if err == nil && info.IsDir() {
+			paths = append(paths, vendor)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return paths
+		}
+
+		dir = parent
+	}
+}
+
+// Init creates an empty manifest in dir.
+func Init(dir string) (*T, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(filepath.Join(dir, Manifest))
+	if err == nil {
+		return nil, fmt.Errorf("%s already exists", filepath.Join(dir, Manifest))
+	}
+
+	p := &project{Locked: map[string]Locked{}, Root: dir}
+
+	return p, p.Save()
+}
+
+// Open reads the manifest and lock file for the project rooted at dir. A
+// missing lock file is treated as empty.
+func Open(dir string) (*T, error) {
+	p := &project{Root: dir}
+
+	path := filepath.Join(dir, Manifest)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p.Dependencies, err = ParseManifest(path, f)
+	if err != nil {
+		return nil, err
+	}
+
+	path = filepath.Join(dir, Lockfile)
+
+	l, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		p.Locked = map[string]Locked{}
+
+		return p, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	p.Locked, err = ParseLockfile(path, l)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ParseManifest reads dependencies from r. Each non-blank, non-comment
+// line is a dependency:
+//
+//	NAME SOURCE [VERSION]
+//
+// The version defaults to HEAD, the source's default branch.
+func ParseManifest(label string, r io.Reader) ([]Dependency, error) {
+	deps := []Dependency{}
+	seen := map[string]bool{}
+
+	err := fields(r, func(n int, f []string) error {
+		if len(f) < 2 || len(f) > 3 {
+			return fmt.Errorf("%s:%d: expected NAME SOURCE [VERSION]", label, n)
+		}
+
+		d := Dependency{Name: f[0], Source: f[1], Version: "HEAD"}
+		if len(f) == 3 {
+			d.Version = f[2]
+		}
+
+		err := d.check()
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", label, n, err)
+		}
+
+		if seen[d.Name] {
+			return fmt.Errorf("%s:%d: duplicate dependency '%s'", label, n, d.Name)
+		}
+
+		seen[d.Name] = true
+
+		deps = append(deps, d)
+
+		return nil
+	})
+
+	return deps, err
+}
+
+// ParseLockfile reads locked dependencies from r. Each non-blank,
+// non-comment line is a locked dependency:
+//
+//	NAME VERSION COMMIT SUM
+func ParseLockfile(label string, r io.Reader) (map[string]Locked, error) {
+	locked := map[string]Locked{}
+
+	err := fields(r, func(n int, f []string) error {
+		if len(f) != 4 {
+			return fmt.Errorf("%s:%d: expected NAME VERSION COMMIT SUM", label, n)
+		}
+
+		locked[f[0]] = Locked{Name: f[0], Version: f[1], Commit: f[2], Sum: f[3]}
+
+		return nil
+	})
+
+	return locked, err
+}
+
+// Dependency returns the dependency called name, or nil.
+func (p *project) Dependency(name string) *Dependency {
+	for i := range p.Dependencies {
+		if p.Dependencies[i].Name == name {
+			return &p.Dependencies[i]
+		}
+	}
+
+	return nil
+}
+
+// Save writes the project's manifest and lock file.
+func (p *project) Save() error {
+	var m strings.Builder
+
+	m.WriteString("# Module dependencies: NAME SOURCE VERSION\n")
+
+	for _, d := range p.Dependencies {
+		fmt.Fprintf(&m, "%s %s %s\n", d.Name, d.Source, d.Version)
+	}
+
+	err := os.WriteFile(filepath.Join(p.Root, Manifest), []byte(m.String()), 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(p.Locked))
+	for name := range p.Locked {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var l strings.Builder
+
+	l.WriteString("# Generated by oh mod. Do not edit.\n")
+
+	for _, name := range names {
+		v := p.Locked[name]
+		fmt.Fprintf(&l, "%s %s %s %s\n", v.Name, v.Version, v.Commit, v.Sum)
+	}
+
+	return os.WriteFile(filepath.Join(p.Root, Lockfile), []byte(l.String()), 0o644) //nolint:gosec
+}
+
+func fields(r io.Reader, f func(n int, fields []string) error) error {
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err := f(n, strings.Fields(line))
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+// A source or version that starts with a dash could be passed to git as an
+// option.
+func (d Dependency) check() error {
+	err := validate(d.Name)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(d.Source, "-") {
+		return fmt.Errorf("invalid source '%s'", d.Source)
+	}
+
+	if strings.HasPrefix(d.Version, "-") && d.Version != Unversioned {
+		return fmt.Errorf("invalid version '%s'", d.Version)
+	}
+
+	return nil
+}
+
+func validate(name string) error {
+	if name == "." || name == ".." || strings.HasPrefix(name, ".") ||
+		strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid module name '%s'", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+// Released under an MIT license. See LICENSE.
+
+package mod
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	deps, err := ParseManifest("test", strings.NewReader(`
+# Comments and blank lines are ignored.
+
+greet ../greet v1
+util https://example.com/util.git
+local ./local -
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deps) != 3 || deps[0].Version != "v1" || deps[1].Version != "HEAD" || deps[2].Version != Unversioned {
+		t.Fatalf("unexpected dependencies %+v", deps)
+	}
+
+	_, err = ParseManifest("test", strings.NewReader("a ../a\na ../b\n"))
+	if err == nil || err.Error() != "test:2: duplicate dependency 'a'" {
+		t.Fatalf("expected duplicate dependency error, got %v", err)
+	}
+
+	_, err = ParseManifest("test", strings.NewReader("../a ../a\n"))
+	if err == nil || err.Error() != "test:1: invalid module name '../a'" {
+		t.Fatalf("expected invalid module name error, got %v", err)
+	}
+
+	_, err = ParseManifest("test", strings.NewReader("evil --upload-pack=touch@x\n"))
+	if err == nil || err.Error() != "test:1: invalid source '--upload-pack=touch@x'" {
+		t.Fatalf("expected invalid source error, got %v", err)
+	}
+
+	_, err = ParseManifest("test", strings.NewReader("a ../a --output=x\n"))
+	if err == nil || err.Error() != "test:1: invalid version '--output=x'" {
+		t.Fatalf("expected invalid version error, got %v", err)
+	}
+}
+
+func TestProject(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_EMAIL":    "oh@example.com",
+		"GIT_AUTHOR_NAME":     "oh",
+		"GIT_COMMITTER_EMAIL": "oh@example.com",
+		"GIT_COMMITTER_NAME":  "oh",
+		"GIT_CONFIG_GLOBAL":   os.DevNull,
+		"GIT_CONFIG_NOSYSTEM": "1",
+	} {
+		t.Setenv(k, v)
+	}
+
+	tmp := t.TempDir()
+
+	repo := filepath.Join(tmp, "greet")
+	run(t, "", "init", "--quiet", repo)
+
+	v1 := commit(t, repo, "greet.oh", "v1\n")
+	run(t, repo, "tag", "v1")
+
+	root := filepath.Join(tmp, "project")
+	write(t, root, "README", "")
+
+	err := Main(root, []string{"init"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Main(root, []string{"add", "greet", "../greet", "v1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vendored := filepath.Join(root, Vendor, "greet", "greet.oh")
+	expect(t, vendored, "v1\n")
+
+	if paths := Paths(filepath.Join(root, "sub")); len(paths) != 1 || paths[0] != filepath.Join(root, Vendor) {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+
+	var out strings.Builder
+
+	err = Main(root, []string{"list"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(out.String(), "greet  v1  "+v1[:12]+"  ../greet") {
+		t.Fatalf("unexpected list %q", out.String())
+	}
+
+	// Tampering with vendored files is detected and fixed by install.
+	write(t, filepath.Join(root, Vendor, "greet"), "greet.oh", "tampered\n")
+
+	err = Main(root, []string{"verify"}, &out)
+	if !errors.Is(err, ErrVerify) {
+		t.Fatalf("expected verification to fail, got %v", err)
+	}
+
+	err = Main(root, []string{"install"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, vendored, "v1\n")
+
+	// Install uses the locked commit, upgrade resolves the version again.
+	v2 := commit(t, repo, "greet.oh", "v2\n")
+	run(t, repo, "tag", "--force", "v1")
+
+	err = Main(root, []string{"install"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, vendored, "v1\n")
+
+	out.Reset()
+
+	err = Main(root, []string{"upgrade"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "greet: "+v1[:12]+" -> "+v2[:12]+"\n" {
+		t.Fatalf("unexpected upgrade %q", out.String())
+	}
+
+	expect(t, vendored, "v2\n")
+
+	err = Main(root, []string{"upgrade", "greet", "nonexistent"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown version 'nonexistent'") {
+		t.Fatalf("expected unknown version error, got %v", err)
+	}
+
+	// Plain directories are copied as is and checked against the lock file.
+	write(t, filepath.Join(tmp, "plain"), "plain.oh", "plain\n")
+
+	err = Main(root, []string{"add", "plain", "../plain", Unversioned}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write(t, filepath.Join(tmp, "plain"), "plain.oh", "changed\n")
+
+	err = Main(root, []string{"install"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "plain: checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	err = Main(root, []string{"remove", "plain"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Dependencies) != 1 || len(p.Locked) != 1 || p.Locked["greet"].Commit != v2 {
+		t.Fatalf("unexpected project %+v", p)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, Vendor, "plain")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected plain to be removed, got %v", err)
+	}
+}
+
+func commit(t *testing.T, repo, name, content string) string {
+	t.Helper()
+
+	write(t, repo, name, content)
+	run(t, repo, "add", name)
+	run(t, repo, "commit", "--quiet", "-m", content)
+
+	return run(t, repo, "rev-parse", "HEAD")
+}
+
+func expect(t *testing.T, path, content string) {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != content {
+		t.Fatalf("expected %q in %s, got %q", content, path, string(b))
+	}
+}
+
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := git(dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
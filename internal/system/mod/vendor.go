@@ -0,0 +1,341 @@
+// Released under an MIT license. See LICENSE.
+
+package mod
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Sum returns the checksum of the files in dir. Only regular files are
+// included and a top-level .git directory is skipped.
+func Sum(dir string) (string, error) {
+	summary := sha256.New()
+
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if e.IsDir() && e.Name() == ".git" && filepath.Dir(path) == filepath.Clean(dir) {
+			return filepath.SkipDir
+		}
+
+		if !e.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+
+		_, err = io.Copy(h, f)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(summary, "%x  %s\n", h.Sum(nil), filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
+
+// Install vendors each of the project's dependencies. A dependency that is
+// locked at its current version is vendored at the locked commit and must
+// match the locked checksum. Otherwise the dependency's version is resolved
+// and the result is locked.
+func (p *project) Install() error {
+	for _, d := range p.Dependencies {
+		_, err := p.vendor(d, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.prune()
+
+	return p.Save()
+}
+
+// Upgrade resolves the versions of the named dependencies, or of all
+// dependencies if no names are given, again and vendors the result. The
+// previously locked state of each upgraded dependency is returned.
+func (p *project) Upgrade(names ...string) (map[string]Locked, error) {
+	if len(names) == 0 {
+		for _, d := range p.Dependencies {
+			names = append(names, d.Name)
+		}
+	}
+
+	previous := map[string]Locked{}
+
+	for _, name := range names {
+		d := p.Dependency(name)
+		if d == nil {
+			return nil, fmt.Errorf("no dependency '%s'", name)
+		}
+
+		previous[name] = p.Locked[name]
+
+		_, err := p.vendor(*d, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return previous, p.Save()
+}
+
+// Verify compares the checksum of each vendored dependency with the
+// checksum in the lock file. It returns a problem, or "", for each of the
+// project's dependencies.
+func (p *project) Verify() map[string]string {
+	problems := map[string]string{}
+
+	for _, d := range p.Dependencies {
+		problems[d.Name] = ""
+
+		l, ok := p.Locked[d.Name]
+		if !ok {
+			problems[d.Name] = "not locked"
+
+			continue
+		}
+
+		dir := filepath.Join(p.Root, Vendor, d.Name)
+
+		_, err := os.Stat(dir)
+		if err != nil {
+			problems[d.Name] = "not vendored"
+
+			continue
+		}
+
+		sum, err := Sum(dir)
+		if err != nil {
+			problems[d.Name] = err.Error()
+		} else if sum != l.Sum {
+			problems[d.Name] = "checksum mismatch: " + Lockfile + " has " + l.Sum + ", vendored files have " + sum
+		}
+	}
+
+	return problems
+}
+
+// Remove removes a dependency, and its vendored files, from the project.
+func (p *project) Remove(name string) error {
+	deps := p.Dependencies[:0]
+
+	for _, d := range p.Dependencies {
+		if d.Name != name {
+			deps = append(deps, d)
+		}
+	}
+
+	if len(deps) == len(p.Dependencies) {
+		return fmt.Errorf("no dependency '%s'", name)
+	}
+
+	p.Dependencies = deps
+
+	delete(p.Locked, name)
+
+	err := os.RemoveAll(filepath.Join(p.Root, Vendor, name))
+	if err != nil {
+		return err
+	}
+
+	return p.Save()
+}
+
+// Prune removes lock file entries for dependencies not in the manifest.
+func (p *project) prune() {
+	for name := range p.Locked {
+		if p.Dependency(name) == nil {
+			delete(p.Locked, name)
+		}
+	}
+}
+
+// Source returns the git URL or absolute path for a dependency's source.
+func (p *project) source(d Dependency) string {
+	s := d.Source
+	if strings.Contains(s, "://") || strings.Contains(s, "@") || filepath.IsAbs(s) {
+		return s
+	}
+
+	return filepath.Join(p.Root, s)
+}
+
+func (p *project) vendor(d Dependency, upgrade bool) (Locked, error) {
+	l, locked := p.Locked[d.Name]
+	locked = locked && l.Version == d.Version && !upgrade
+
+	src := p.source(d)
+
+	commit := Unversioned
+
+	if d.Version != Unversioned {
+		ref := d.Version
+		if locked {
+			ref = l.Commit
+		}
+
+		tmp, err := os.MkdirTemp("", "oh-mod-")
+		if err != nil {
+			return l, err
+		}
+		defer os.RemoveAll(tmp)
+
+		commit, err = checkout(src, ref, tmp)
+		if err != nil {
+			return l, fmt.Errorf("%s: %w", d.Name, err)
+		}
+
+		src = tmp
+	}
+
+	sum, err := Sum(src)
+	if err != nil {
+		return l, fmt.Errorf("%s: %w", d.Name, err)
+	}
+
+	if locked && sum != l.Sum {
+		return l, fmt.Errorf(
+			"%s: checksum mismatch: %s has %s, source has %s (run 'oh mod upgrade %s' to accept)",
+			d.Name, Lockfile, l.Sum, sum, d.Name,
+		)
+	}
+
+	dst := filepath.Join(p.Root, Vendor, d.Name)
+
+	err = os.RemoveAll(dst)
+	if err != nil {
+		return l, err
+	}
+
+	err = copyTree(src, dst)
+	if err != nil {
+		return l, fmt.Errorf("%s: %w", d.Name, err)
+	}
+
+	l = Locked{Name: d.Name, Version: d.Version, Commit: commit, Sum: sum}
+	p.Locked[d.Name] = l
+
+	return l, nil
+}
+
+// Checkout clones the git repository at src into dir, checks out ref and
+// returns the resolved commit. The ref can be a branch, a tag or a commit.
+func checkout(src, ref, dir string) (string, error) {
+	_, err := git("", "clone", "--quiet", "--no-checkout", "--", src, dir)
+	if err != nil {
+		return "", err
+	}
+
+	commit := ""
+
+	for _, candidate := range []string{"refs/remotes/origin/" + ref, ref} {
+		commit, err = git(dir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}")
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unknown version '%s'", ref)
+	}
+
+	_, err = git(dir, "checkout", "--quiet", "--detach", commit)
+
+	return commit, err
+}
+
+// CopyTree copies the directories and regular files under src to dst.
+// A top-level .git directory is skipped.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if e.IsDir() && e.Name() == ".git" && filepath.Dir(path) == filepath.Clean(src) {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case e.IsDir():
+			return os.MkdirAll(target, 0o755) //nolint:gosec
+		case e.Type().IsRegular():
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			return os.WriteFile(target, b, info.Mode().Perm())
+		}
+
+		return nil
+	})
+}
+
+func git(dir string, args ...string) (string, error) {
+	name := args[0]
+
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	var stderr, stdout bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stderr = &stderr
+	cmd.Stdout = &stdout
+
+	err := cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+
+		return "", fmt.Errorf("git %s: %s", name, msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
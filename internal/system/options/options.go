@@ -12,7 +12,10 @@ var (
 	args        []string
 	command     string
 	interactive bool
+	mod         bool
 	monitor     bool
+	policy      string
+	restricted  bool
 	script      string
 	terminal    int
 	version     bool
@@ -20,9 +23,10 @@ var (
 	usage = `oh
 
 Usage:
-  oh [-m] SCRIPT [ARGUMENTS...]
-  oh [-m] -c COMMAND [NAME [ARGUMENTS...]]
-  oh [-im] [-s [ARGUMENTS...]]
+  oh mod [ARGUMENTS...]
+  oh [-mr] [-p FILE] SCRIPT [ARGUMENTS...]
+  oh [-mr] [-p FILE] -c COMMAND [NAME [ARGUMENTS...]]
+  oh [-imr] [-p FILE] [-s [ARGUMENTS...]]
   oh -h
   oh -v
 
@@ -31,10 +35,16 @@ Arguments:
   SCRIPT     Path to oh script. Also used as the value for $0.
   NAME       Override $0. Otherwise, $0 is set to name used to invoke oh.
 
+Commands:
+  mod        Manage module dependencies. See 'oh mod' for details. To run a
+             script called mod use './mod'.
+
 Options:
   -c, --command=COMMAND  Run the specified command.
   -m, --monitor          Invert job control mode.
   -i, --interactive      Disable interactive mode.
+  -r, --restricted       Run in restricted mode.
+  -p, --policy=FILE      Restricted mode policy. Implies --restricted.
   -s, --stdin            Read commands from stdin.
   -h, --help             Display this help.
   -v, --version          Print oh version.
@@ -42,6 +52,10 @@ Options:
 If oh's stdin is a TTY, and oh was invoked with no non-option operands or
 oh was explicitly directed to evaluate commands from stdin, interactive and
 job control features are enabled. Otherwise, these features are disabled.
+
+In restricted mode oh can only run the commands, and open the files, allowed
+by its policy. It cannot change PATH or PWD. Without a policy file, nothing
+is allowed.
 `
 )
 
@@ -60,6 +74,11 @@ func Interactive() bool {
 	return interactive
 }
 
+// Mod returns true if oh was invoked to manage module dependencies.
+func Mod() bool {
+	return mod
+}
+
 // Parse parses the command line options for this invocation of oh.
 func Parse() {
 	docopt.DefaultParser.OptionsFirst = true
@@ -74,6 +93,8 @@ func Parse() {
 
 	command, _ = opts.String("--command")
 
+	mod, _ = opts.Bool("mod")
+
 	name, _ := opts.String("NAME")
 	if name == "" {
 		name = os.Args[0]
@@ -100,6 +121,23 @@ func Parse() {
 	monitor = monitor != invertMonitor
 
 	version, _ = opts.Bool("--version")
+
+	restricted, _ = opts.Bool("--restricted")
+
+	policy, _ = opts.String("--policy")
+	if policy != "" {
+		restricted = true
+	}
+}
+
+// Policy returns the path to the restricted mode policy file (if any).
+func Policy() string {
+	return policy
+}
+
+// Restricted returns true if oh should run in restricted mode.
+func Restricted() bool {
+	return restricted
 }
 
 // Script returns the script name (if any).
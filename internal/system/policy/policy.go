@@ -0,0 +1,297 @@
+// Released under an MIT license. See LICENSE.
+
+// Package policy provides the rules enforced when oh runs in restricted mode.
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/michaelmacinnis/oh/internal/common/type/exception"
+)
+
+// T (policy) lists what a restricted oh is allowed to do.
+//
+// Tasks are counted individually rather than by job. Each stage of a
+// pipeline but the last is a spawned task, as is each background command
+// and each use of spawn, so a limit of n tasks allows a pipeline of at most
+// n + 1 stages.
+type T struct {
+	Commands []string // Allowed commands. Names without a slash match any directory.
+	Read     []string // Paths that can be opened for reading.
+	Write    []string // Paths that can be opened for writing.
+
+	Memory uint64 // Maximum heap size in bytes. Zero means no limit.
+	Tasks  int64  // Maximum number of concurrently spawned tasks. Zero means no limit.
+}
+
+type policy = T
+
+// Violation is raised when a restricted oh attempts something its policy forbids.
+type Violation struct {
+	Rule   string
+	Detail string
+}
+
+// Error returns the violation as a string.
+func (v *Violation) Error() string {
+	return "restricted: " + v.Rule + " rule forbids " + v.Detail
+}
+
+// Variables that cannot be changed in restricted mode.
+//
+//nolint:gochecknoglobals
+var protected = []string{"PATH", "PWD", "OLDPWD"}
+
+// Load reads a policy from the file at path.
+func Load(path string) (*T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(path, f)
+}
+
+// Parse reads a policy from r. Each non-blank, non-comment line is a rule
+// name followed by a value:
+//
+//	command NAME|PATH
+//	read PATTERN
+//	write PATTERN
+//	memory BYTES[K|M|G]
+//	tasks COUNT
+//
+// Patterns are shell-style globs. A pattern that ends with a slash
+// matches everything below that directory. The tasks rule limits spawned
+// tasks, not jobs. See T.
+func Parse(label string, r io.Reader) (*T, error) {
+	p := &policy{}
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 { //nolint:gomnd
+			return nil, fmt.Errorf("%s:%d: expected rule and value", label, n) //nolint:goerr113
+		}
+
+		rule, value := fields[0], fields[1]
+
+		var err error
+
+		switch rule {
+		case "command":
+			p.Commands = append(p.Commands, value)
+		case "read":
+			p.Read = append(p.Read, value)
+		case "write":
+			p.Write = append(p.Write, value)
+		case "memory":
+			p.Memory, err = bytes(value)
+		case "tasks":
+			p.Tasks, err = strconv.ParseInt(value, 10, 64)
+		default:
+			err = fmt.Errorf("unknown rule '%s'", rule) //nolint:goerr113
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", label, n, err)
+		}
+	}
+
+	return p, s.Err()
+}
+
+// Enforce makes p the active policy. A nil policy lifts all restrictions.
+func Enforce(p *T) {
+	active.Lock()
+	defer active.Unlock()
+
+	active.policy = p
+	atomic.StoreInt64(&tasks, 0)
+
+	if p != nil && p.Memory > 0 {
+		debug.SetMemoryLimit(int64(p.Memory))
+	}
+}
+
+// Restricted returns true if a policy is being enforced.
+func Restricted() bool {
+	return current() != nil
+}
+
+// Command panics if the command name, found at path, is not allowed.
+func Command(name, path string) {
+	p := current()
+	if p == nil {
+		return
+	}
+
+	for _, allowed := range p.Commands {
+		if strings.ContainsRune(allowed, os.PathSeparator) {
+			if match(allowed, path) {
+				return
+			}
+		} else if allowed == name || allowed == filepath.Base(path) {
+			return
+		}
+	}
+
+	panic(&Violation{"command", "'" + name + "'"})
+}
+
+// Memory panics if the heap has grown beyond the allowed limit.
+// To keep this cheap it only samples the heap every so often.
+func Memory() {
+	p := current()
+	if p == nil || p.Memory == 0 {
+		return
+	}
+
+	if atomic.AddUint64(&ticks, 1)%sampleRate != 0 {
+		return
+	}
+
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+
+	if used := sample[0].Value.Uint64(); used > p.Memory {
+		panic(&Violation{"memory", "using " + strconv.FormatUint(used, 10) + " bytes"})
+	}
+}
+
+// Open panics if path cannot be opened for reading and/or writing.
+func Open(path string, read, write bool) {
+	p := current()
+	if p == nil {
+		return
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		panic(exception.Wrap(exception.IO, err))
+	}
+
+	if read && !matchAny(p.Read, abs) {
+		panic(&Violation{"read", "'" + path + "'"})
+	}
+
+	if write && !matchAny(p.Write, abs) {
+		panic(&Violation{"write", "'" + path + "'"})
+	}
+}
+
+// Spawned panics if another task would exceed the allowed number of tasks.
+// Otherwise it returns a function to call when the spawned task finishes.
+func Spawned() func() {
+	p := current()
+	if p == nil {
+		return nil
+	}
+
+	n := atomic.AddInt64(&tasks, 1)
+	if p.Tasks > 0 && n > p.Tasks {
+		atomic.AddInt64(&tasks, -1)
+		panic(&Violation{"tasks", "more than " + strconv.FormatInt(p.Tasks, 10) + " tasks"})
+	}
+
+	return func() {
+		atomic.AddInt64(&tasks, -1)
+	}
+}
+
+// Variable panics if the variable k cannot be changed.
+func Variable(k string) {
+	if current() == nil {
+		return
+	}
+
+	for _, v := range protected {
+		if k == v {
+			panic(&Violation{"variable", "changing " + k})
+		}
+	}
+}
+
+const sampleRate = 1024
+
+//nolint:gochecknoglobals
+var (
+	active struct {
+		sync.RWMutex
+		policy *policy
+	}
+
+	tasks int64
+	ticks uint64
+)
+
+func bytes(s string) (uint64, error) {
+	multiplier := uint64(1)
+
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K', 'k':
+			multiplier = 1 << 10
+		case 'M', 'm':
+			multiplier = 1 << 20
+		case 'G', 'g':
+			multiplier = 1 << 30
+		}
+
+		if multiplier > 1 {
+			s = s[:n-1]
+		}
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return v * multiplier, nil
+}
+
+func current() *policy {
+	active.RLock()
+	defer active.RUnlock()
+
+	return active.policy
+}
+
+func match(pattern, path string) bool {
+	if strings.HasSuffix(pattern, string(os.PathSeparator)) {
+		dir := filepath.Clean(pattern)
+
+		return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+	}
+
+	ok, err := filepath.Match(pattern, path)
+
+	return err == nil && ok
+}
+
+func matchAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, path) {
+			return true
+		}
+	}
+
+	return false
+}
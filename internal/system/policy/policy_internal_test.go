@@ -0,0 +1,121 @@
+// Released under an MIT license. See LICENSE.
+
+package policy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const rules = `
+# Comments and blank lines are ignored.
+
+command echo
+command /usr/bin/*
+read /srv/data/
+write /tmp/*.log
+memory 64M
+tasks 2
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse("test", strings.NewReader(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Commands) != 2 || len(p.Read) != 1 || len(p.Write) != 1 {
+		t.Fatalf("unexpected policy %+v", p)
+	}
+
+	if p.Memory != 64<<20 || p.Tasks != 2 {
+		t.Fatalf("unexpected limits %+v", p)
+	}
+
+	_, err = Parse("test", strings.NewReader("chdir /\n"))
+	if err == nil || err.Error() != "test:1: unknown rule 'chdir'" {
+		t.Fatalf("expected unknown rule error, got %v", err)
+	}
+}
+
+func TestRules(t *testing.T) {
+	p, err := Parse("test", strings.NewReader(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Enforce(p)
+	defer Enforce(nil)
+
+	allowed := []func(){
+		func() { Command("echo", "/bin/echo") },
+		func() { Command("id", "/usr/bin/id") },
+		func() { Open("/srv/data/a/b", true, false) },
+		func() { Open("/tmp/oh.log", false, true) },
+		func() { Variable("HOME") },
+	}
+
+	for i, f := range allowed {
+		if v := violation(f); v != nil {
+			t.Fatalf("case %d: unexpected violation: %v", i, v)
+		}
+	}
+
+	forbidden := map[string]func(){
+		"command":  func() { Command("rm", "/bin/rm") },
+		"read":     func() { Open("/srv/database", true, false) },
+		"write":    func() { Open("/srv/data/x", true, true) },
+		"variable": func() { Variable("PATH") },
+	}
+
+	for rule, f := range forbidden {
+		v := violation(f)
+		if v == nil || v.Rule != rule {
+			t.Fatalf("expected %s violation, got %v", rule, v)
+		}
+	}
+
+	first, second := Spawned(), Spawned()
+
+	if v := violation(func() { Spawned() }); v == nil || v.Rule != "tasks" {
+		t.Fatalf("expected tasks violation, got %v", v)
+	}
+
+	first()
+	second()
+
+	if v := violation(func() { Spawned()() }); v != nil {
+		t.Fatalf("unexpected violation: %v", v)
+	}
+}
+
+func TestUnrestricted(t *testing.T) {
+	Enforce(nil)
+
+	if v := violation(func() { Command("rm", "/bin/rm") }); v != nil {
+		t.Fatalf("unexpected violation: %v", v)
+	}
+
+	if Spawned() != nil {
+		t.Fatal("unrestricted spawn should not be tracked")
+	}
+}
+
+func violation(f func()) (v *Violation) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		err, ok := r.(error)
+		if !ok || !errors.As(err, &v) {
+			panic(r)
+		}
+	}()
+
+	f()
+
+	return nil
+}
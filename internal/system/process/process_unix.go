@@ -103,9 +103,9 @@ func Stop(pid int) {
 	_ = unix.Kill(pid, unix.SIGSTOP)
 }
 
-// SysProcAttr returns the appropriate *unix.SysProcAttr given the group ID
-// and if this is for the foreground group.
-func SysProcAttr(foreground bool, group int) *unix.SysProcAttr {
+// SysProcAttr returns the appropriate *unix.SysProcAttr given the group ID,
+// if this is for the foreground group, and the sandbox s (which may be nil).
+func SysProcAttr(foreground bool, group int, s *Sandbox) *unix.SysProcAttr {
 	sys := &unix.SysProcAttr{Foreground: foreground, Setpgid: true}
 
 	if group == 0 {
@@ -114,6 +114,10 @@ func SysProcAttr(foreground bool, group int) *unix.SysProcAttr {
 		sys.Pgid = group
 	}
 
+	if s != nil {
+		s.apply(sys)
+	}
+
 	return sys
 }
 
@@ -0,0 +1,161 @@
+// Released under an MIT license. See LICENSE.
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/michaelmacinnis/adapted"
+	"github.com/michaelmacinnis/oh/internal/common/interface/cell"
+)
+
+const sandboxName = "sandbox"
+
+// IDMap maps a range of IDs inside a user namespace to IDs outside of it.
+type IDMap struct {
+	Inside  int
+	Outside int
+	Size    int
+}
+
+// Sandbox describes the namespaces, root directory and control group
+// for processes started within the extent of an oh sandbox.
+type Sandbox struct {
+	IPC   bool
+	Mount bool
+	Net   bool
+	PID   bool
+	User  bool
+	UTS   bool
+
+	Chroot string
+
+	GIDMap []IDMap
+	UIDMap []IDMap
+
+	Cgroup string
+	CPU    string
+	Memory string
+
+	cgroup  *os.File
+	created string
+}
+
+// Dir returns the working directory a sandboxed process should use given
+// the current working directory pwd.
+func (s *Sandbox) Dir(pwd string) string {
+	if s.Chroot == "" {
+		return pwd
+	}
+
+	rel, err := filepath.Rel(s.Chroot, pwd)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return string(os.PathSeparator)
+	}
+
+	return filepath.Join(string(os.PathSeparator), rel)
+}
+
+// Equal returns true if c is the same sandbox as s.
+func (s *Sandbox) Equal(c cell.I) bool {
+	o, ok := c.(*Sandbox)
+
+	return ok && o == s
+}
+
+// LookPath searches for the executable name as it would be seen from inside
+// the sandbox. The path returned is the path inside the sandbox.
+func (s *Sandbox) LookPath(name, path, pwd string) (string, bool, error) {
+	if s.Chroot == "" {
+		return adapted.LookPath(name, path)
+	}
+
+	sep := string(os.PathSeparator)
+
+	prefix := name + "   "
+	if prefix[0:1] == sep || prefix[0:2] == "."+sep || prefix[0:3] == ".."+sep {
+		host := name
+		if !filepath.IsAbs(host) {
+			host = filepath.Join(s.Dir(pwd), host)
+		}
+
+		_, exe, err := adapted.LookPath(filepath.Join(s.Chroot, host), "")
+
+		return name, exe, err
+	}
+
+	dirs := strings.Split(path, string(os.PathListSeparator))
+	for i, dir := range dirs {
+		dirs[i] = filepath.Join(s.Chroot, dir)
+	}
+
+	host, exe, err := adapted.LookPath(name, strings.Join(dirs, string(os.PathListSeparator)))
+	if err != nil {
+		return "", false, err
+	}
+
+	return filepath.Join(sep, strings.TrimPrefix(host, s.Chroot)), exe, nil
+}
+
+// Name returns the name of the sandbox type.
+func (s *Sandbox) Name() string {
+	return sandboxName
+}
+
+// Prepare checks that sandboxes are supported, makes the sandbox's root
+// directory absolute and creates and configures its control group, if any.
+func (s *Sandbox) Prepare() error {
+	err := supported()
+	if err != nil {
+		return err
+	}
+
+	if s.Chroot != "" {
+		s.Chroot, err = filepath.Abs(s.Chroot)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Cgroup == "" {
+		return nil
+	}
+
+	s.cgroup, s.created, err = cgroup(s.Cgroup, s.CPU, s.Memory)
+	if err != nil {
+		return err
+	}
+
+	runtime.SetFinalizer(s, (*Sandbox).Release)
+
+	return nil
+}
+
+// Release removes the sandbox's control group if it was created for the
+// sandbox and no processes remain in it. A control group that is still in
+// use is kept until the sandbox is released again or garbage collected.
+func (s *Sandbox) Release() {
+	if s.created != "" {
+		if os.Remove(s.created) != nil {
+			return
+		}
+
+		s.created = ""
+	}
+
+	if s.cgroup != nil {
+		_ = s.cgroup.Close()
+		s.cgroup = nil
+	}
+}
+
+// A compiler-checked list of interfaces this type satisfies. Never called.
+func implements() { //nolint:deadcode,unused
+	var t Sandbox
+
+	// The sandbox type is a cell.
+	_ = cell.I(&t)
+}
@@ -0,0 +1,164 @@
+// Released under an MIT license. See LICENSE.
+
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"golang.org/x/sys/unix"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+var errNotCgroup2 = common.Error("not in a cgroup v2 hierarchy") //nolint:gochecknoglobals
+
+func (s *Sandbox) apply(sys *unix.SysProcAttr) {
+	flags := map[uintptr]bool{
+		unix.CLONE_NEWIPC:  s.IPC,
+		unix.CLONE_NEWNET:  s.Net,
+		unix.CLONE_NEWNS:   s.Mount,
+		unix.CLONE_NEWPID:  s.PID,
+		unix.CLONE_NEWUSER: s.User,
+		unix.CLONE_NEWUTS:  s.UTS,
+	}
+
+	for flag, set := range flags {
+		if set {
+			sys.Cloneflags |= flag
+		}
+	}
+
+	if s.PID {
+		// A process in a new PID namespace cannot join a process
+		// group outside of that namespace. It gets its own group.
+		sys.Pgid = 0
+	}
+
+	sys.Chroot = s.Chroot
+
+	if s.User {
+		uids := s.UIDMap
+		if len(uids) == 0 {
+			uids = []IDMap{{Inside: 0, Outside: os.Getuid(), Size: 1}}
+		}
+
+		gids := s.GIDMap
+		if len(gids) == 0 {
+			gids = []IDMap{{Inside: 0, Outside: os.Getgid(), Size: 1}}
+		}
+
+		sys.UidMappings = idMappings(uids)
+		sys.GidMappings = idMappings(gids)
+		sys.GidMappingsEnableSetgroups = false
+	}
+
+	if s.cgroup != nil {
+		sys.UseCgroupFD = true
+		sys.CgroupFD = int(s.cgroup.Fd())
+	}
+}
+
+// cgroup opens the control group at path, creating it if necessary, and
+// sets its limits. If the control group was created, its path is returned.
+func cgroup(path, cpu, memory string) (*os.File, string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cgroupRoot, path)
+	}
+
+	err := cgroup2(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	created := ""
+
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		created = path
+	}
+
+	err = os.MkdirAll(path, 0o755)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limits := map[string]string{
+		"cpu.max":    cpu,
+		"memory.max": memory,
+	}
+
+	for name, value := range limits {
+		if value == "" {
+			continue
+		}
+
+		err = os.WriteFile(filepath.Join(path, name), []byte(value), 0o644)
+		if err != nil {
+			if created != "" {
+				_ = os.Remove(created)
+			}
+
+			return nil, "", err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, created, nil
+}
+
+// cgroup2 checks that the closest existing ancestor of path (or path
+// itself) is part of a cgroup v2 hierarchy.
+func cgroup2(path string) error {
+	for {
+		var fs unix.Statfs_t
+
+		err := unix.Statfs(path, &fs)
+		if err == nil {
+			if fs.Type != unix.CGROUP2_SUPER_MAGIC {
+				return errNotCgroup2
+			}
+
+			return nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return errNotCgroup2
+		}
+
+		path = parent
+	}
+}
+
+func supported() error {
+	return nil
+}
+
+func idMappings(m []IDMap) []syscall.SysProcIDMap {
+	mappings := make([]syscall.SysProcIDMap, len(m))
+
+	for i, v := range m {
+		mappings[i] = syscall.SysProcIDMap{
+			ContainerID: v.Inside,
+			HostID:      v.Outside,
+			Size:        v.Size,
+		}
+	}
+
+	return mappings
+}
@@ -0,0 +1,74 @@
+// Released under an MIT license. See LICENSE.
+
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSandboxDir(t *testing.T) {
+	s := &Sandbox{Chroot: "/srv/root"}
+
+	for pwd, expected := range map[string]string{
+		"/srv/root":        "/",
+		"/srv/root/home/a": "/home/a",
+		"/srv":             "/",
+		"/srv/rootless":    "/",
+	} {
+		if actual := s.Dir(pwd); actual != expected {
+			t.Fatalf("Dir(%q): expected %q, got %q", pwd, expected, actual)
+		}
+	}
+}
+
+func TestSandboxLookPath(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.Mkdir(filepath.Join(dir, "bin"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "bin", "tool"), nil, 0o755) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The root directory is cleaned so that it is a prefix of the paths found.
+	s := &Sandbox{Chroot: dir + "/bin/../"}
+
+	err = s.Prepare()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, exe, err := s.LookPath("tool", "/bin", "/")
+	if err != nil || !exe || path != "/bin/tool" {
+		t.Fatalf("expected /bin/tool, got %q %v %v", path, exe, err)
+	}
+}
+
+func TestSandboxUserNamespace(t *testing.T) {
+	c := exec.Command("id", "-u")
+
+	c.SysProcAttr = SysProcAttr(false, 0, &Sandbox{
+		User:   true,
+		UIDMap: []IDMap{{Inside: 42, Outside: os.Getuid(), Size: 1}},
+	})
+
+	out, err := c.Output()
+	if err != nil {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+
+	if s := strings.TrimSpace(string(out)); s != "42" {
+		t.Fatalf("expected 42, got %s", s)
+	}
+}
@@ -0,0 +1,26 @@
+// Released under an MIT license. See LICENSE.
+
+//go:build aix || darwin || dragonfly || freebsd || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd netbsd openbsd solaris
+
+package process
+
+import (
+	"os"
+
+	"github.com/michaelmacinnis/oh/internal/common"
+	"golang.org/x/sys/unix"
+)
+
+var errUnsupported = common.Error("sandboxes are only supported on Linux") //nolint:gochecknoglobals
+
+// apply is never called as Prepare rejects every sandbox on this platform.
+func (s *Sandbox) apply(_ *unix.SysProcAttr) {}
+
+func cgroup(_, _, _ string) (*os.File, string, error) {
+	return nil, "", errUnsupported
+}
+
+func supported() error {
+	return errUnsupported
+}
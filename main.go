@@ -20,7 +20,9 @@ import (
 	"github.com/michaelmacinnis/oh/internal/system/cache"
 	"github.com/michaelmacinnis/oh/internal/system/history"
 	"github.com/michaelmacinnis/oh/internal/system/job"
+	"github.com/michaelmacinnis/oh/internal/system/mod"
 	"github.com/michaelmacinnis/oh/internal/system/options"
+	"github.com/michaelmacinnis/oh/internal/system/policy"
 	"github.com/michaelmacinnis/oh/internal/system/process"
 	"github.com/peterh/liner"
 )
@@ -329,6 +331,29 @@ func skip(dirname, basename string) bool {
 	return false
 }
 
+func restrictions() *policy.T {
+	p := &policy.T{}
+
+	if options.Policy() != "" {
+		var err error
+
+		p, err = policy.Load(options.Policy())
+		if err != nil {
+			println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if script := options.Script(); script != "" {
+		abs, err := filepath.Abs(script)
+		if err == nil {
+			p.Read = append(p.Read, abs)
+		}
+	}
+
+	return p
+}
+
 func split(s string) (head, tail string) {
 	head = s
 	tail = ""
@@ -425,6 +450,20 @@ func main() {
 		return
 	}
 
+	if options.Mod() {
+		err := mod.Main(".", options.Args()[1:], os.Stdout)
+		if err != nil {
+			println(err.Error())
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if options.Restricted() {
+		engine.Restrict(restrictions())
+	}
+
 	engine.Boot(options.Script(), options.Args())
 
 	if !command() && !interactive() {
@@ -434,11 +473,18 @@ func main() {
 
 //go:generate ./oh bin/test.oh
 //go:generate ./oh bin/doc.oh manual ../doc/manual.md
+//go:generate ./oh bin/type-common.oh internal/common/type/blob
 //go:generate ./oh bin/type-common.oh internal/common/type/chn
+//go:generate ./oh bin/type-common.oh internal/common/type/duration
 //go:generate ./oh bin/type-common.oh internal/common/type/env
+//go:generate ./oh bin/type-common.oh internal/common/type/exception
+//go:generate ./oh bin/type-common.oh internal/common/type/hmap
 //go:generate ./oh bin/type-common.oh internal/common/type/num
 //go:generate ./oh bin/type-common.oh internal/common/type/obj
 //go:generate ./oh bin/type-common.oh internal/common/type/pair
 //go:generate ./oh bin/type-common.oh internal/common/type/pipe
+//go:generate ./oh bin/type-common.oh internal/common/type/pipeline
+//go:generate ./oh bin/type-common.oh internal/common/type/regex
 //go:generate ./oh bin/type-common.oh internal/common/type/status
 //go:generate ./oh bin/type-common.oh internal/common/type/str
+//go:generate ./oh bin/type-common.oh internal/common/type/timestamp